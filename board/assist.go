@@ -0,0 +1,70 @@
+package board
+
+// AutoFlag flags every closed cell that the solver proves to be a mine,
+// without counting clicks, and returns the number of flagged cells
+func (b *Board) AutoFlag() int {
+	if b.state != Playing {
+		return 0
+	}
+	s := newSolver(b)
+	s.deduce()
+	flagged := 0
+	for i, mine := range s.mine {
+		cell := &b.cells[i/b.width][i%b.width]
+		if mine && !cell.Marked {
+			cell.Marked = true
+			cell.Question = false
+			b.remaining--
+			flagged++
+		}
+	}
+	b.assists += flagged
+	return flagged
+}
+
+// AutoOpen chords, without counting clicks, every number whose flags match
+// it and are all proven mines, so that only safe cells are opened, until
+// nothing changes and returns the number of opened cells
+func (b *Board) AutoOpen() int {
+	opened := 0
+	for b.state == Playing {
+		s := newSolver(b)
+		s.deduce()
+		closed := b.closed
+		for i := range s.open {
+			x, y := i%b.width, i/b.width
+			if !b.cells[y][x].Open || !b.isSatisfied(x, y, s) {
+				continue
+			}
+			b.ForEachNeighbour(x, y, func(x, y int) {
+				b.open(x, y)
+			})
+		}
+		if b.closed == closed {
+			break
+		}
+		opened += closed - b.closed
+		b.checkWon()
+	}
+	b.assists += opened
+	return opened
+}
+
+// isSatisfied reports whether the flags next to an open cell are all proven
+// mines and match its number
+func (b *Board) isSatisfied(x, y int, s *solver) bool {
+	marked, proven := 0, true
+	b.ForEachNeighbour(x, y, func(nx, ny int) {
+		if b.cells[ny][nx].Marked {
+			marked++
+			proven = proven && s.mine[ny*b.width+nx]
+		}
+	})
+	return proven && marked == b.cells[y][x].Number
+}
+
+// Assists gets the number of cells that were flagged or opened by AutoFlag
+// and AutoOpen during the game
+func (b *Board) Assists() int {
+	return b.assists
+}
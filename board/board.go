@@ -0,0 +1,490 @@
+package board
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// State is the state of the game on a board
+type State int
+
+const (
+	// Waiting means no cell has been opened yet
+	Waiting State = iota
+	// Playing means the mines are placed and the game is running
+	Playing
+	// Won means all cells without a mine are open
+	Won
+	// Lost means a cell with a mine was opened
+	Lost
+)
+
+var stateNames = [...]string{"waiting", "playing", "won", "lost"}
+
+// String gets the name of the state
+func (s State) String() string {
+	if s < 0 || int(s) >= len(stateNames) {
+		return "unknown"
+	}
+	return stateNames[s]
+}
+
+// Placement decides which cells stay free of mines on the first click
+type Placement int
+
+const (
+	// Classic keeps only the first opened cell free of mines
+	Classic Placement = iota
+	// Opening keeps the first opened cell and its neighbours free of mines
+	Opening
+	// NoGuess is like Opening, but only accepts boards that can be solved
+	// from the first opened cell without guessing
+	NoGuess
+)
+
+var placementNames = [...]string{"classic", "opening", "noguess"}
+
+// String gets the name of the placement
+func (p Placement) String() string {
+	if p < 0 || int(p) >= len(placementNames) {
+		return "unknown"
+	}
+	return placementNames[p]
+}
+
+// ParsePlacement gets the placement with the given name
+func ParsePlacement(name string) (Placement, error) {
+	for i, placementName := range placementNames {
+		if name == placementName {
+			return Placement(i), nil
+		}
+	}
+	return Classic, fmt.Errorf("Unknown placement '%s'", name)
+}
+
+// MarshalText gets the name of the placement for JSON
+func (p Placement) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText sets the placement from its name in JSON
+func (p *Placement) UnmarshalText(text []byte) error {
+	placement, err := ParsePlacement(string(text))
+	*p = placement
+	return err
+}
+
+// safeCells gets the number of cells that are kept free of mines
+func (p Placement) safeCells() int {
+	if p == Classic {
+		return 1
+	}
+	return 9
+}
+
+// Config holds the size, mine count, seed and rules of a board, in
+// practice mode a move can be undone, also after hitting a mine
+type Config struct {
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	Mines     int       `json:"mines"`
+	Seed      int64     `json:"seed"`
+	Placement Placement `json:"placement"`
+	Questions bool      `json:"questions"`
+	Practice  bool      `json:"practice,omitempty"`
+}
+
+// The smallest and largest boards that the game offers
+const (
+	MinWidth  = 9
+	MaxWidth  = 30
+	MinHeight = 9
+	MaxHeight = 24
+)
+
+// maxNoGuessDensity is the number of cells per mine that NoGuess needs at
+// least, denser boards can often not be repaired to be solved by logic
+const maxNoGuessDensity = 4
+
+// Validate checks whether or not the mines fit on the board
+func (c Config) Validate() error {
+	if c.Width < 1 || c.Height < 1 {
+		return fmt.Errorf("Size %dx%d is too small", c.Width, c.Height)
+	}
+	if c.Mines < 1 {
+		return fmt.Errorf("Need at least 1 mine")
+	}
+	if max := c.Width*c.Height - c.Placement.safeCells(); c.Mines > max {
+		return fmt.Errorf("Too many mines, %dx%d fits %d", c.Width, c.Height, max)
+	}
+	if max := c.Width * c.Height / maxNoGuessDensity; c.Placement == NoGuess && c.Mines > max {
+		return fmt.Errorf("Too many mines for no guessing, %dx%d fits %d", c.Width, c.Height, max)
+	}
+	return nil
+}
+
+// MarshalText gets the name of the state for JSON
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText sets the state from its name in JSON
+func (s *State) UnmarshalText(text []byte) error {
+	for i, name := range stateNames {
+		if string(text) == name {
+			*s = State(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown state '%s'", text)
+}
+
+// Cell is a single tile on the board
+type Cell struct {
+	Open     bool
+	Marked   bool
+	Question bool
+	Mine     bool
+	Number   int
+}
+
+// Board is a minesweeper field with its rules
+type Board struct {
+	width     int
+	height    int
+	mines     int
+	seed      int64
+	placement Placement
+	questions bool
+	practice  bool
+	remaining int
+	closed    int
+	state     State
+	ticks     int
+	elapsed   time.Duration
+	threeBV   int
+
+	leftClicks  int
+	rightClicks int
+	chordClicks int
+	hints       int
+	assists     int
+	undos       int
+	guessing    bool
+
+	cells   [][]Cell
+	history []step
+}
+
+// New creates a new board, the same config and first opened cell
+// always lead to the same placement of the mines
+func New(c Config) *Board {
+	b := &Board{
+		width:     c.Width,
+		height:    c.Height,
+		mines:     c.Mines,
+		seed:      c.Seed,
+		placement: c.Placement,
+		questions: c.Questions,
+		practice:  c.Practice,
+	}
+	b.Reset()
+	return b
+}
+
+// Reset clears the board for a new game
+func (b *Board) Reset() {
+	b.remaining = b.mines
+	b.closed = b.width * b.height
+	b.state = Waiting
+	b.ticks = 0
+	b.elapsed = 0
+	b.threeBV = 0
+	b.leftClicks = 0
+	b.rightClicks = 0
+	b.chordClicks = 0
+	b.hints = 0
+	b.assists = 0
+	b.undos = 0
+	b.guessing = false
+	b.history = nil
+	b.cells = make([][]Cell, b.height)
+	for y := 0; y < b.height; y++ {
+		b.cells[y] = make([]Cell, b.width)
+	}
+}
+
+// Width gets the width of the board in cells
+func (b *Board) Width() int {
+	return b.width
+}
+
+// Height gets the height of the board in cells
+func (b *Board) Height() int {
+	return b.height
+}
+
+// Mines gets the number of mines on the board
+func (b *Board) Mines() int {
+	return b.mines
+}
+
+// Seed gets the seed that is used to place the mines
+func (b *Board) Seed() int64 {
+	return b.seed
+}
+
+// Config gets the config that the board was created with
+func (b *Board) Config() Config {
+	return Config{
+		Width:     b.width,
+		Height:    b.height,
+		Mines:     b.mines,
+		Seed:      b.seed,
+		Placement: b.placement,
+		Questions: b.questions,
+		Practice:  b.practice,
+	}
+}
+
+// Guessing reports whether a NoGuess board could not be made solvable
+// without guessing, so that the game may need a guess
+func (b *Board) Guessing() bool {
+	return b.guessing
+}
+
+// Placement gets the rule that kept cells free of mines on the first click
+func (b *Board) Placement() Placement {
+	return b.placement
+}
+
+// Remaining gets the number of mines minus the number of marked cells
+func (b *Board) Remaining() int {
+	return b.remaining
+}
+
+// State gets the state of the game
+func (b *Board) State() State {
+	return b.state
+}
+
+// Tick advances the clock by the duration of a tick while playing
+func (b *Board) Tick(d time.Duration) {
+	if b.state != Playing {
+		return
+	}
+	b.ticks++
+	b.elapsed += d
+}
+
+// Ticks gets the number of ticks that the game has been playing
+func (b *Board) Ticks() int {
+	return b.ticks
+}
+
+// Elapsed gets the time that the game has been playing
+func (b *Board) Elapsed() time.Duration {
+	return b.elapsed
+}
+
+// Cell gets the cell at a position
+func (b *Board) Cell(x, y int) Cell {
+	return b.cells[y][x]
+}
+
+// Contains returns whether or not the position is on the board
+func (b *Board) Contains(x, y int) bool {
+	return x >= 0 && y >= 0 && x < b.width && y < b.height
+}
+
+// ForEachNeighbour calls a function for every neighbour of a cell
+func (b *Board) ForEachNeighbour(x, y int, do func(x, y int)) {
+	for i := 0; i < 9; i++ {
+		dy, dx := i/3-1, i%3-1
+		if dy == 0 && dx == 0 {
+			continue
+		}
+		if !b.Contains(x+dx, y+dy) {
+			continue
+		}
+		do(x+dx, y+dy)
+	}
+}
+
+// Open opens a closed cell, placing the mines on the first call
+func (b *Board) Open(x, y int) {
+	if cell := b.cells[y][x]; b.state == Waiting || (!cell.Open && !cell.Marked) {
+		b.remember()
+	}
+	if b.state == Waiting {
+		b.state = Playing
+		b.placeMines(x, y)
+	}
+	if b.state != Playing {
+		return
+	}
+	b.leftClicks++
+	b.open(x, y)
+	b.checkWon()
+}
+
+// Questions returns whether or not cells can be marked with a question
+func (b *Board) Questions() bool {
+	return b.questions
+}
+
+// ToggleFlag marks or unmarks a closed cell, when questions are enabled
+// it cycles from flag to question to blank
+func (b *Board) ToggleFlag(x, y int) {
+	if b.state != Waiting && b.state != Playing {
+		return
+	}
+	b.rightClicks++
+	cell := &b.cells[y][x]
+	if cell.Open {
+		return
+	}
+	b.remember()
+	if cell.Marked {
+		cell.Marked = false
+		cell.Question = b.questions
+		b.remaining++
+	} else if cell.Question {
+		cell.Question = false
+	} else {
+		cell.Marked = true
+		b.remaining--
+	}
+}
+
+// Chord opens the unmarked neighbours of an open cell when the number
+// of marked neighbours matches the number on the cell
+func (b *Board) Chord(x, y int) {
+	if b.state != Playing {
+		return
+	}
+	b.chordClicks++
+	if !b.cells[y][x].Open {
+		return
+	}
+	marked := 0
+	b.ForEachNeighbour(x, y, func(x, y int) {
+		if b.cells[y][x].Marked {
+			marked++
+		}
+	})
+	if b.cells[y][x].Number != marked {
+		return
+	}
+	b.remember()
+	b.ForEachNeighbour(x, y, func(x, y int) {
+		b.open(x, y)
+	})
+	b.checkWon()
+}
+
+func (b *Board) open(x, y int) {
+	cell := &b.cells[y][x]
+	if cell.Open || cell.Marked {
+		return
+	}
+	cell.Open = true
+	cell.Question = false
+	b.closed--
+	if cell.Mine {
+		b.state = Lost
+		return
+	}
+	if cell.Number == 0 {
+		b.ForEachNeighbour(x, y, func(x, y int) {
+			b.open(x, y)
+		})
+	}
+}
+
+func (b *Board) checkWon() {
+	if b.state == Playing && b.closed == b.mines {
+		b.state = Won
+	}
+}
+
+func (b *Board) isSafe(x, y, cx, cy int) bool {
+	if b.placement == Classic {
+		return cx == x && cy == y
+	}
+	return cx >= x-1 && cx <= x+1 && cy >= y-1 && cy <= y+1
+}
+
+// maxRepairs limits the mines that are moved to make a NoGuess board
+// solvable, when that fails a new layout is tried up to maxAttempts times
+// and after that the board is used and reported by Guessing
+const (
+	maxRepairs  = 200
+	maxAttempts = 10
+)
+
+func (b *Board) placeMines(x, y int) {
+	rng := rand.New(rand.NewSource(b.seed))
+	for attempt := 1; ; attempt++ {
+		b.scatterMines(rng, x, y)
+		b.countNumbers()
+		if b.placement != NoGuess || b.repair(rng, x, y) {
+			break
+		}
+		if attempt == maxAttempts {
+			b.guessing = true
+			break
+		}
+		for y := 0; y < b.height; y++ {
+			for x := 0; x < b.width; x++ {
+				b.cells[y][x].Mine = false
+			}
+		}
+	}
+	b.threeBV = b.countThreeBV()
+}
+
+func (b *Board) scatterMines(rng *rand.Rand, x, y int) {
+	m := b.mines
+	for m > 0 {
+		cx, cy := rng.Intn(b.width), rng.Intn(b.height)
+		if !b.cells[cy][cx].Mine && !b.isSafe(x, y, cx, cy) {
+			b.cells[cy][cx].Mine = true
+			m--
+		}
+	}
+}
+
+// repair solves the board from the first opened cell and, when the solver
+// gets stuck, moves a mine from the cells it is stuck on to a closed cell
+// away from the open area, until the board can be solved without guessing
+func (b *Board) repair(rng *rand.Rand, x, y int) bool {
+	s := newSolver(b)
+	if s.solvable(x, y) {
+		return true
+	}
+	for repairs := 0; repairs < maxRepairs; repairs++ {
+		if !s.moveMine(rng) {
+			return false
+		}
+		if s.solve() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Board) countNumbers() {
+	for y := 0; y < b.height; y++ {
+		for x := 0; x < b.width; x++ {
+			number := 0
+			b.ForEachNeighbour(x, y, func(x, y int) {
+				if b.cells[y][x].Mine {
+					number++
+				}
+			})
+			b.cells[y][x].Number = number
+		}
+	}
+}
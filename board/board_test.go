@@ -0,0 +1,80 @@
+package board
+
+import "testing"
+
+// mineRows draws the mines of a board as rows with a "*" for every mine
+func mineRows(b *Board) []string {
+	rows := []string{}
+	for y := 0; y < b.Height(); y++ {
+		row := ""
+		for x := 0; x < b.Width(); x++ {
+			if b.Cell(x, y).Mine {
+				row += "*"
+			} else {
+				row += "."
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestPlacementIsReproducible(t *testing.T) {
+	tests := []struct {
+		placement Placement
+		rows      []string
+	}{
+		{Classic, []string{
+			".....*...",
+			".........",
+			".*......*",
+			".....*...",
+			"...*.....",
+			"........*",
+			".........",
+			"..*.*....",
+			"......*.*",
+		}},
+		{NoGuess, []string{
+			".*...*...",
+			".........",
+			".*......*",
+			".........",
+			".........",
+			"........*",
+			".......*.",
+			"..*.*....",
+			"......*.*",
+		}},
+	}
+	for _, test := range tests {
+		for i := 0; i < 2; i++ {
+			b := New(Config{Width: 9, Height: 9, Mines: 10, Seed: 42, Placement: test.placement})
+			b.Open(4, 4)
+			rows := mineRows(b)
+			for y := range rows {
+				if rows[y] != test.rows[y] {
+					t.Fatalf("%s: row %d is %q, expected %q", test.placement, y, rows[y], test.rows[y])
+				}
+			}
+		}
+	}
+}
+
+func TestFirstOpenIsSafe(t *testing.T) {
+	for seed := int64(1); seed <= 100; seed++ {
+		for _, placement := range []Placement{Classic, Opening, NoGuess} {
+			b := New(Config{Width: 9, Height: 9, Mines: 10, Seed: seed, Placement: placement})
+			b.Open(0, 8)
+			if b.State() != Playing {
+				t.Fatalf("%s seed %d: state is %s after the first open", placement, seed, b.State())
+			}
+			if placement != Classic && b.Cell(0, 8).Number != 0 {
+				t.Fatalf("%s seed %d: first open has number %d", placement, seed, b.Cell(0, 8).Number)
+			}
+			if b.Guessing() {
+				t.Fatalf("%s seed %d: needs a guess", placement, seed)
+			}
+		}
+	}
+}
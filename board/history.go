@@ -0,0 +1,68 @@
+package board
+
+// maxHistory limits the number of moves that can be undone
+const maxHistory = 100
+
+// step is the state of the board before a move in practice mode
+type step struct {
+	cells     [][]Cell
+	remaining int
+	closed    int
+	state     State
+	threeBV   int
+}
+
+// remember stores the state before a move so that it can be undone, in
+// practice mode only
+func (b *Board) remember() {
+	if !b.practice || (b.state != Waiting && b.state != Playing) {
+		return
+	}
+	cells := make([][]Cell, b.height)
+	for y := range cells {
+		cells[y] = append([]Cell{}, b.cells[y]...)
+	}
+	if len(b.history) == maxHistory {
+		b.history = b.history[1:]
+	}
+	b.history = append(b.history, step{
+		cells:     cells,
+		remaining: b.remaining,
+		closed:    b.closed,
+		state:     b.state,
+		threeBV:   b.threeBV,
+	})
+}
+
+// Practice returns whether or not moves can be undone
+func (b *Board) Practice() bool {
+	return b.practice
+}
+
+// CanUndo reports whether there is a move to undo, a won game can not be
+// undone
+func (b *Board) CanUndo() bool {
+	return len(b.history) > 0 && b.state != Won
+}
+
+// Undo restores the tiles and counters from before the last move, also
+// when that move hit a mine, and reports whether there was a move to undo
+func (b *Board) Undo() bool {
+	if !b.CanUndo() {
+		return false
+	}
+	s := b.history[len(b.history)-1]
+	b.history = b.history[:len(b.history)-1]
+	b.cells = s.cells
+	b.remaining = s.remaining
+	b.closed = s.closed
+	b.state = s.state
+	b.threeBV = s.threeBV
+	b.undos++
+	return true
+}
+
+// Undos gets the number of moves that were undone during the game
+func (b *Board) Undos() int {
+	return b.undos
+}
@@ -0,0 +1,47 @@
+package board
+
+import "testing"
+
+func TestUndoAfterLoss(t *testing.T) {
+	b := New(Config{Width: 9, Height: 9, Mines: 10, Seed: 42, Practice: true})
+	b.Open(4, 4)
+	b.ToggleFlag(3, 4)
+	before := b.Snapshot()
+	b.Open(5, 0)
+	if b.State() != Lost {
+		t.Fatalf("state is %s after opening a mine, expected lost", b.State())
+	}
+	if !b.Undo() {
+		t.Fatal("undo after hitting a mine failed")
+	}
+	after := b.Snapshot()
+	if after.State != Playing || after.Closed != before.Closed || after.Remaining != before.Remaining {
+		t.Fatalf("undo restored %s with %d closed and %d remaining, expected playing with %d and %d",
+			after.State, after.Closed, after.Remaining, before.Closed, before.Remaining)
+	}
+	for y := range before.Cells {
+		for x := range before.Cells[y] {
+			if after.Cells[y][x] != before.Cells[y][x] {
+				t.Fatalf("cell (%d,%d) is %d after undo, expected %d", x, y, after.Cells[y][x], before.Cells[y][x])
+			}
+		}
+	}
+	if b.Undos() != 1 {
+		t.Fatalf("undos is %d, expected 1", b.Undos())
+	}
+	if !b.Undo() || !b.Undo() || b.State() != Waiting || b.Undo() {
+		t.Fatal("undo did not go back to the start of the game")
+	}
+}
+
+func TestUndoNeedsPractice(t *testing.T) {
+	b := New(Config{Width: 9, Height: 9, Mines: 10, Seed: 42})
+	b.Open(4, 4)
+	b.Open(5, 0)
+	if b.CanUndo() || b.Undo() {
+		t.Fatal("undo outside practice mode")
+	}
+	if b.State() != Lost {
+		t.Fatalf("state is %s, expected lost", b.State())
+	}
+}
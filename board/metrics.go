@@ -0,0 +1,83 @@
+package board
+
+import "time"
+
+// Metrics holds the numbers that players use to compare games
+type Metrics struct {
+	ThreeBV     int
+	LeftClicks  int
+	RightClicks int
+	ChordClicks int
+	Elapsed     time.Duration
+}
+
+// Metrics gets the 3BV, the clicks and the elapsed time of the game
+func (b *Board) Metrics() Metrics {
+	return Metrics{
+		ThreeBV:     b.threeBV,
+		LeftClicks:  b.leftClicks,
+		RightClicks: b.rightClicks,
+		ChordClicks: b.chordClicks,
+		Elapsed:     b.elapsed,
+	}
+}
+
+// Clicks gets the total number of clicks
+func (m Metrics) Clicks() int {
+	return m.LeftClicks + m.RightClicks + m.ChordClicks
+}
+
+// ThreeBVPerSecond gets the 3BV divided by the elapsed seconds
+func (m Metrics) ThreeBVPerSecond() float64 {
+	if m.Elapsed <= 0 {
+		return 0
+	}
+	return float64(m.ThreeBV) / m.Elapsed.Seconds()
+}
+
+// Efficiency gets the 3BV as a percentage of the clicks
+func (m Metrics) Efficiency() int {
+	if m.Clicks() == 0 {
+		return 0
+	}
+	return m.ThreeBV * 100 / m.Clicks()
+}
+
+// countThreeBV counts the minimum number of clicks that are needed to open
+// all cells without a mine: one click per opening (a connected area of
+// cells without a number) plus one per numbered cell that is not next to
+// an opening
+func (b *Board) countThreeBV() int {
+	marked := make([][]bool, b.height)
+	for y := range marked {
+		marked[y] = make([]bool, b.width)
+	}
+	var flood func(x, y int)
+	flood = func(x, y int) {
+		if marked[y][x] {
+			return
+		}
+		marked[y][x] = true
+		if b.cells[y][x].Number == 0 {
+			b.ForEachNeighbour(x, y, flood)
+		}
+	}
+	count := 0
+	for y := 0; y < b.height; y++ {
+		for x := 0; x < b.width; x++ {
+			cell := b.cells[y][x]
+			if !marked[y][x] && !cell.Mine && cell.Number == 0 {
+				flood(x, y)
+				count++
+			}
+		}
+	}
+	for y := 0; y < b.height; y++ {
+		for x := 0; x < b.width; x++ {
+			if !marked[y][x] && !b.cells[y][x].Mine {
+				count++
+			}
+		}
+	}
+	return count
+}
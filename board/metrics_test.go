@@ -0,0 +1,64 @@
+package board
+
+import "testing"
+
+// newTestBoard creates a board that is playing with mines at the "*" in
+// the rows
+func newTestBoard(rows ...string) *Board {
+	mines := 0
+	for _, row := range rows {
+		for _, c := range row {
+			if c == '*' {
+				mines++
+			}
+		}
+	}
+	b := New(Config{Width: len(rows[0]), Height: len(rows), Mines: mines})
+	for y, row := range rows {
+		for x, c := range row {
+			b.cells[y][x].Mine = c == '*'
+		}
+	}
+	b.countNumbers()
+	b.state = Playing
+	b.threeBV = b.countThreeBV()
+	return b
+}
+
+func TestThreeBV(t *testing.T) {
+	tests := []struct {
+		rows    []string
+		threeBV int
+	}{
+		{[]string{"*.*", "...", "..."}, 2},
+		{[]string{"*.*", ".*."}, 3},
+		{[]string{"..*."}, 2},
+		{[]string{"*....", ".....", "....*"}, 1},
+	}
+	for _, test := range tests {
+		b := newTestBoard(test.rows...)
+		if got := b.Metrics().ThreeBV; got != test.threeBV {
+			t.Errorf("%q: 3BV is %d, expected %d", test.rows, got, test.threeBV)
+		}
+	}
+}
+
+func TestClicks(t *testing.T) {
+	b := newTestBoard("*.*", "...", "...")
+	b.Open(1, 0)
+	b.ToggleFlag(0, 0)
+	b.ToggleFlag(2, 0)
+	b.Chord(1, 0)
+	for x := 0; x < 3; x++ {
+		if !b.Cell(x, 1).Open || b.Cell(x, 2).Open {
+			t.Fatalf("chord did not open only the neighbours of (1,0)")
+		}
+	}
+	b.Open(1, 2)
+	if b.State() != Won {
+		t.Fatalf("state is %s, expected won", b.State())
+	}
+	if m := b.Metrics(); m.LeftClicks != 2 || m.RightClicks != 2 || m.ChordClicks != 1 {
+		t.Fatalf("clicks are %d+%d+%d, expected 2+2+1", m.LeftClicks, m.RightClicks, m.ChordClicks)
+	}
+}
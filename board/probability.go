@@ -0,0 +1,319 @@
+package board
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxNodes limits the number of partial mine arrangements that are tried
+// by Probabilities, so that a huge open front can not freeze the game
+const maxNodes = 1000000
+
+// component is a group of unknown cells that share constraints, with the
+// number of mine arrangements per number of mines in the group
+type component struct {
+	cells       []int
+	constraints []constraint
+	ways        []float64
+	mines       [][]float64
+}
+
+// Probabilities gets for every cell the chance that it holds a mine, worked
+// out from the open numbers and the number of mines, flags are ignored and
+// open cells get 0. It counts as a hint while the game is not over.
+func (b *Board) Probabilities() ([][]float64, error) {
+	b.countHint()
+	return b.probabilities()
+}
+
+// Hint finds a closed cell that is certainly safe, preferring cells without
+// a flag. It counts as a hint while the game is not over.
+func (b *Board) Hint() (x, y int, ok bool) {
+	b.countHint()
+	switch b.state {
+	case Waiting:
+		return b.width / 2, b.height / 2, true
+	case Won, Lost:
+		return 0, 0, false
+	}
+	var safe func(i int) bool
+	p, err := b.probabilities()
+	if err == nil {
+		safe = func(i int) bool { return p[i/b.width][i%b.width] == 0 }
+	} else {
+		s := newSolver(b)
+		s.deduce()
+		safe = func(i int) bool { return s.safe[i] }
+	}
+	found := -1
+	for i := 0; i < b.width*b.height; i++ {
+		cell := b.cells[i/b.width][i%b.width]
+		if cell.Open || !safe(i) {
+			continue
+		}
+		if !cell.Marked {
+			return i % b.width, i / b.width, true
+		}
+		if found < 0 {
+			found = i
+		}
+	}
+	if found < 0 {
+		return 0, 0, false
+	}
+	return found % b.width, found / b.width, true
+}
+
+// Hints gets the number of times a hint was asked during the game
+func (b *Board) Hints() int {
+	return b.hints
+}
+
+func (b *Board) countHint() {
+	if b.state == Waiting || b.state == Playing {
+		b.hints++
+	}
+}
+
+func (b *Board) probabilities() ([][]float64, error) {
+	s := newSolver(b)
+	s.deduce()
+	components := s.components()
+	nodes := 0
+	for _, c := range components {
+		if err := c.enumerate(&nodes); err != nil {
+			return nil, err
+		}
+	}
+	remaining, others, frontier := b.mines, 0, 0
+	for i := range s.open {
+		if s.mine[i] {
+			remaining--
+		} else if s.isUnknown(i) {
+			others++
+		}
+	}
+	for _, c := range components {
+		others -= len(c.cells)
+		frontier += len(c.cells)
+	}
+	binomial := scaledBinomial(others, remaining-frontier, remaining)
+	// rest gets the arrangements of all components except one per number
+	// of mines, including the cells outside of the components
+	rest := func(skip int) []float64 {
+		dist := []float64{1}
+		for j, c := range components {
+			if j != skip {
+				dist = convolve(dist, c.ways)
+			}
+		}
+		return dist
+	}
+	weight := func(dist []float64, mines int) float64 {
+		w := 0.0
+		for t, ways := range dist {
+			w += ways * binomial(mines-t)
+		}
+		return w
+	}
+	all := rest(-1)
+	total := weight(all, remaining)
+	if total == 0 {
+		return nil, fmt.Errorf("No arrangement of mines fits the numbers")
+	}
+	p := make([][]float64, b.height)
+	for y := range p {
+		p[y] = make([]float64, b.width)
+	}
+	for j, c := range components {
+		dist := rest(j)
+		for k := range c.ways {
+			w := weight(dist, remaining-k)
+			for n, i := range c.cells {
+				p[i/b.width][i%b.width] += c.mines[k][n] * w / total
+			}
+		}
+	}
+	othersChance := 0.0
+	if others > 0 {
+		for f, ways := range all {
+			m := remaining - f
+			othersChance += ways * binomial(m) * float64(m) / float64(others) / total
+		}
+	}
+	inComponent := map[int]bool{}
+	for _, c := range components {
+		for _, i := range c.cells {
+			inComponent[i] = true
+		}
+	}
+	for i := range s.open {
+		if s.mine[i] {
+			p[i/b.width][i%b.width] = 1
+		} else if s.isUnknown(i) && !inComponent[i] {
+			p[i/b.width][i%b.width] = othersChance
+		}
+	}
+	return p, nil
+}
+
+// components groups the unknown cells next to open numbers into components
+// that do not share any constraint
+func (s *solver) components() []*component {
+	constraints := s.constraints()
+	group := map[int]int{}
+	var find func(i int) int
+	find = func(i int) int {
+		if group[i] == i {
+			return i
+		}
+		group[i] = find(group[i])
+		return group[i]
+	}
+	for _, c := range constraints {
+		for _, i := range c.cells {
+			if _, ok := group[i]; !ok {
+				group[i] = i
+			}
+		}
+		for _, i := range c.cells[1:] {
+			group[find(i)] = find(c.cells[0])
+		}
+	}
+	byRoot := map[int]*component{}
+	components := []*component{}
+	for _, c := range constraints {
+		root := find(c.cells[0])
+		comp, ok := byRoot[root]
+		if !ok {
+			comp = &component{}
+			byRoot[root] = comp
+			components = append(components, comp)
+		}
+		comp.constraints = append(comp.constraints, c)
+	}
+	for _, comp := range components {
+		seen := map[int]bool{}
+		for _, c := range comp.constraints {
+			for _, i := range c.cells {
+				if !seen[i] {
+					seen[i] = true
+					comp.cells = append(comp.cells, i)
+				}
+			}
+		}
+	}
+	return components
+}
+
+// enumerate counts the mine arrangements that satisfy all constraints
+func (c *component) enumerate(nodes *int) error {
+	index := map[int]int{}
+	for n, i := range c.cells {
+		index[i] = n
+	}
+	byCell := make([][]int, len(c.cells))
+	for j, con := range c.constraints {
+		for _, i := range con.cells {
+			byCell[index[i]] = append(byCell[index[i]], j)
+		}
+	}
+	mines := make([]int, len(c.constraints))
+	open := make([]int, len(c.constraints))
+	for j, con := range c.constraints {
+		open[j] = len(con.cells)
+	}
+	c.ways = make([]float64, len(c.cells)+1)
+	c.mines = make([][]float64, len(c.cells)+1)
+	for k := range c.mines {
+		c.mines[k] = make([]float64, len(c.cells))
+	}
+	assigned := make([]bool, len(c.cells))
+	var place func(n, count int) error
+	place = func(n, count int) error {
+		*nodes++
+		if *nodes > maxNodes {
+			return fmt.Errorf("Too many arrangements of mines to work out")
+		}
+		if n == len(c.cells) {
+			c.ways[count]++
+			for m, mine := range assigned {
+				if mine {
+					c.mines[count][m]++
+				}
+			}
+			return nil
+		}
+		for _, mine := range []bool{false, true} {
+			fits := true
+			for _, j := range byCell[n] {
+				open[j]--
+				if mine {
+					mines[j]++
+				}
+				if mines[j] > c.constraints[j].count || mines[j]+open[j] < c.constraints[j].count {
+					fits = false
+				}
+			}
+			assigned[n] = mine
+			var err error
+			if fits {
+				if mine {
+					err = place(n+1, count+1)
+				} else {
+					err = place(n+1, count)
+				}
+			}
+			for _, j := range byCell[n] {
+				open[j]++
+				if mine {
+					mines[j]--
+				}
+			}
+			assigned[n] = false
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return place(0, 0)
+}
+
+// convolve combines the arrangements per number of mines of two groups
+func convolve(a, b []float64) []float64 {
+	c := make([]float64, len(a)+len(b)-1)
+	for i, x := range a {
+		if x == 0 {
+			continue
+		}
+		for j, y := range b {
+			c[i+j] += x * y
+		}
+	}
+	return c
+}
+
+// scaledBinomial gets n choose k for k in the range low-high, divided by
+// the largest of them so that large boards do not overflow
+func scaledBinomial(n, low, high int) func(k int) float64 {
+	lchoose := func(k int) float64 {
+		a, _ := math.Lgamma(float64(n + 1))
+		b, _ := math.Lgamma(float64(k + 1))
+		c, _ := math.Lgamma(float64(n - k + 1))
+		return a - b - c
+	}
+	scale := math.Inf(-1)
+	for k := maxInt(low, 0); k <= minInt(high, n); k++ {
+		scale = math.Max(scale, lchoose(k))
+	}
+	if math.IsInf(scale, -1) {
+		scale = 0
+	}
+	return func(k int) float64 {
+		if k < 0 || k > n {
+			return 0
+		}
+		return math.Exp(lchoose(k) - scale)
+	}
+}
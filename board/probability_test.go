@@ -0,0 +1,92 @@
+package board
+
+import (
+	"math"
+	"testing"
+)
+
+// countProbabilities works out the chances by trying every arrangement of
+// the mines on the closed cells
+func countProbabilities(b *Board) [][]float64 {
+	closed := []int{}
+	for i := 0; i < b.width*b.height; i++ {
+		if !b.cells[i/b.width][i%b.width].Open {
+			closed = append(closed, i)
+		}
+	}
+	counts := make([]float64, len(closed))
+	total := 0.0
+	for set := 0; set < 1<<len(closed); set++ {
+		mines := map[int]bool{}
+		for j, i := range closed {
+			if set&(1<<j) != 0 {
+				mines[i] = true
+			}
+		}
+		if len(mines) != b.mines {
+			continue
+		}
+		fits := true
+		for i := 0; i < b.width*b.height && fits; i++ {
+			x, y := i%b.width, i/b.width
+			if !b.cells[y][x].Open {
+				continue
+			}
+			n := 0
+			b.ForEachNeighbour(x, y, func(x, y int) {
+				if mines[y*b.width+x] {
+					n++
+				}
+			})
+			fits = n == b.cells[y][x].Number
+		}
+		if !fits {
+			continue
+		}
+		total++
+		for j, i := range closed {
+			if mines[i] {
+				counts[j]++
+			}
+		}
+	}
+	p := make([][]float64, b.height)
+	for y := range p {
+		p[y] = make([]float64, b.width)
+	}
+	for j, i := range closed {
+		p[i/b.width][i%b.width] = counts[j] / total
+	}
+	return p
+}
+
+func TestProbabilities(t *testing.T) {
+	b := newTestBoard(
+		"*...",
+		"...*",
+		"....",
+		".*..",
+	)
+	for _, i := range []int{5, 6, 9, 10} {
+		b.cells[i/4][i%4].Open = true
+	}
+	want := countProbabilities(b)
+	p, err := b.Probabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := range want {
+		for x := range want[y] {
+			if math.Abs(p[y][x]-want[y][x]) > 1e-9 {
+				t.Fatalf("chance on %d,%d is %f, want %f", x, y, p[y][x], want[y][x])
+			}
+		}
+	}
+	if b.Hints() != 1 {
+		t.Fatalf("%d hints counted", b.Hints())
+	}
+	x, y, ok := b.Hint()
+	if ok && (b.cells[y][x].Open || want[y][x] != 0) {
+		t.Fatalf("hint on %d,%d is not a safe closed cell", x, y)
+	}
+}
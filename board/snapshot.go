@@ -0,0 +1,135 @@
+package board
+
+import (
+	"fmt"
+	"time"
+)
+
+// Bits of a cell in a snapshot, the number is stored in the bits above
+// these flags, so a cell is Number<<4 | flags
+const (
+	snapshotMine = 1 << iota
+	snapshotOpen
+	snapshotMarked
+	snapshotQuestion
+)
+
+// Snapshot is the complete state of a board, the elapsed time is in
+// milliseconds and the cells are stored as rows of integers that have bit 1
+// set for a mine, bit 2 for open, bit 4 for marked, bit 8 for a question
+// and the number multiplied by 16, the moves that can be undone are not
+// part of it
+type Snapshot struct {
+	Config      Config  `json:"config"`
+	State       State   `json:"state"`
+	Remaining   int     `json:"remaining"`
+	Closed      int     `json:"closed"`
+	Ticks       int     `json:"ticks"`
+	Elapsed     int64   `json:"elapsed"`
+	LeftClicks  int     `json:"leftClicks"`
+	RightClicks int     `json:"rightClicks"`
+	ChordClicks int     `json:"chordClicks"`
+	Hints       int     `json:"hints,omitempty"`
+	Assists     int     `json:"assists,omitempty"`
+	Undos       int     `json:"undos,omitempty"`
+	Cells       [][]int `json:"cells"`
+}
+
+// Snapshot gets the complete state of the board
+func (b *Board) Snapshot() Snapshot {
+	s := Snapshot{
+		Config:      b.Config(),
+		State:       b.state,
+		Remaining:   b.remaining,
+		Closed:      b.closed,
+		Ticks:       b.ticks,
+		Elapsed:     b.elapsed.Milliseconds(),
+		LeftClicks:  b.leftClicks,
+		RightClicks: b.rightClicks,
+		ChordClicks: b.chordClicks,
+		Hints:       b.hints,
+		Assists:     b.assists,
+		Undos:       b.undos,
+		Cells:       make([][]int, b.height),
+	}
+	for y := 0; y < b.height; y++ {
+		s.Cells[y] = make([]int, b.width)
+		for x := 0; x < b.width; x++ {
+			cell := b.cells[y][x]
+			value := cell.Number << 4
+			if cell.Mine {
+				value |= snapshotMine
+			}
+			if cell.Open {
+				value |= snapshotOpen
+			}
+			if cell.Marked {
+				value |= snapshotMarked
+			}
+			if cell.Question {
+				value |= snapshotQuestion
+			}
+			s.Cells[y][x] = value
+		}
+	}
+	return s
+}
+
+// Restore creates a board from a snapshot and checks that the counters
+// match the cells
+func Restore(s Snapshot) (*Board, error) {
+	if err := s.Config.Validate(); err != nil {
+		return nil, err
+	}
+	b := New(s.Config)
+	if len(s.Cells) != b.height {
+		return nil, fmt.Errorf("Snapshot has %d rows, expected %d", len(s.Cells), b.height)
+	}
+	mines, marked, closed := 0, 0, 0
+	for y := 0; y < b.height; y++ {
+		if len(s.Cells[y]) != b.width {
+			return nil, fmt.Errorf("Snapshot row %d has %d cells, expected %d", y, len(s.Cells[y]), b.width)
+		}
+		for x := 0; x < b.width; x++ {
+			value := s.Cells[y][x]
+			cell := Cell{
+				Mine:     value&snapshotMine != 0,
+				Open:     value&snapshotOpen != 0,
+				Marked:   value&snapshotMarked != 0,
+				Question: value&snapshotQuestion != 0,
+				Number:   value >> 4,
+			}
+			if cell.Mine {
+				mines++
+			}
+			if cell.Marked {
+				marked++
+			}
+			if !cell.Open {
+				closed++
+			}
+			b.cells[y][x] = cell
+		}
+	}
+	if s.State != Waiting && mines != b.mines {
+		return nil, fmt.Errorf("Snapshot has %d mines, expected %d", mines, b.mines)
+	}
+	if closed != s.Closed || b.mines-marked != s.Remaining {
+		return nil, fmt.Errorf("Snapshot counters do not match its cells")
+	}
+	b.state = s.State
+	b.remaining = s.Remaining
+	b.closed = s.Closed
+	b.ticks = s.Ticks
+	b.elapsed = time.Duration(s.Elapsed) * time.Millisecond
+	b.leftClicks = s.LeftClicks
+	b.rightClicks = s.RightClicks
+	b.chordClicks = s.ChordClicks
+	b.hints = s.Hints
+	b.assists = s.Assists
+	b.undos = s.Undos
+	if b.state != Waiting {
+		b.threeBV = b.countThreeBV()
+	}
+	return b, nil
+}
@@ -0,0 +1,50 @@
+package board
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	b := New(Config{Width: 9, Height: 9, Mines: 10, Seed: 42, Questions: true})
+	b.Open(4, 4)
+	b.ToggleFlag(5, 3)
+	b.ToggleFlag(0, 0)
+	b.ToggleFlag(0, 0)
+	for i := 0; i < 45; i++ {
+		b.Tick(40 * time.Millisecond)
+	}
+	data, err := json.Marshal(b.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Snapshot{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := Restore(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored.Snapshot(), b.Snapshot()) {
+		t.Fatalf("restored snapshot differs:\n%+v\n%+v", restored.Snapshot(), b.Snapshot())
+	}
+	if restored.Metrics() != b.Metrics() {
+		t.Fatalf("restored metrics are %+v, expected %+v", restored.Metrics(), b.Metrics())
+	}
+	if !restored.Cell(0, 0).Question || !restored.Cell(5, 3).Marked {
+		t.Fatalf("restored marks are lost")
+	}
+}
+
+func TestRestoreChecksCounters(t *testing.T) {
+	b := New(Config{Width: 9, Height: 9, Mines: 10, Seed: 42})
+	b.Open(4, 4)
+	s := b.Snapshot()
+	s.Closed--
+	if _, err := Restore(s); err == nil {
+		t.Fatal("snapshot with a wrong closed counter is restored")
+	}
+}
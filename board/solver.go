@@ -0,0 +1,304 @@
+package board
+
+import "math/rand"
+
+// solver deduces which closed cells are safe and which hold a mine, using
+// only the numbers on the open cells and the total number of mines
+type solver struct {
+	b          *Board
+	open       []bool
+	mine       []bool
+	safe       []bool
+	neighbours [][]int
+}
+
+// constraint says that exactly count of the cells hold a mine
+type constraint struct {
+	cells []int
+	count int
+}
+
+func newSolver(b *Board) *solver {
+	n := b.width * b.height
+	s := &solver{
+		b:          b,
+		open:       make([]bool, n),
+		mine:       make([]bool, n),
+		safe:       make([]bool, n),
+		neighbours: make([][]int, n),
+	}
+	for i := 0; i < n; i++ {
+		s.open[i] = b.cells[i/b.width][i%b.width].Open
+		b.ForEachNeighbour(i%b.width, i/b.width, func(x, y int) {
+			s.neighbours[i] = append(s.neighbours[i], y*b.width+x)
+		})
+	}
+	return s
+}
+
+func (s *solver) number(i int) int {
+	return s.b.cells[i/s.b.width][i%s.b.width].Number
+}
+
+// reveal opens a cell the way the board does, including the cascade of
+// cells without a number, used to simulate a game during generation
+func (s *solver) reveal(i int) {
+	if s.open[i] {
+		return
+	}
+	s.open[i] = true
+	s.safe[i] = false
+	if s.number(i) == 0 {
+		for _, n := range s.neighbours[i] {
+			s.reveal(n)
+		}
+	}
+}
+
+func (s *solver) isUnknown(i int) bool {
+	return !s.open[i] && !s.mine[i] && !s.safe[i]
+}
+
+// constraints gets a constraint for every open number next to unknown cells
+func (s *solver) constraints() []constraint {
+	constraints := []constraint{}
+	for i := range s.open {
+		if !s.open[i] {
+			continue
+		}
+		c := constraint{count: s.number(i)}
+		for _, n := range s.neighbours[i] {
+			if s.mine[n] {
+				c.count--
+			} else if s.isUnknown(n) {
+				c.cells = append(c.cells, n)
+			}
+		}
+		if len(c.cells) > 0 {
+			constraints = append(constraints, c)
+		}
+	}
+	return constraints
+}
+
+// mark sets all cells as mine or as safe and reports whether any changed
+func (s *solver) mark(cells []int, mine bool) bool {
+	changed := false
+	for _, i := range cells {
+		if !s.isUnknown(i) {
+			continue
+		}
+		if mine {
+			s.mine[i] = true
+		} else {
+			s.safe[i] = true
+		}
+		changed = true
+	}
+	return changed
+}
+
+// deduce applies the single cell, overlap and mine count rules until
+// nothing new is found and reports whether anything was found
+func (s *solver) deduce() bool {
+	found := false
+	for {
+		changed := s.deduceSingle()
+		if !changed {
+			changed = s.deduceOverlap()
+		}
+		if !changed {
+			changed = s.deduceTotal()
+		}
+		if !changed {
+			return found
+		}
+		found = true
+	}
+}
+
+func (s *solver) deduceSingle() bool {
+	changed := false
+	for _, c := range s.constraints() {
+		if c.count == 0 {
+			changed = s.mark(c.cells, false) || changed
+		} else if c.count == len(c.cells) {
+			changed = s.mark(c.cells, true) || changed
+		}
+	}
+	return changed
+}
+
+func (s *solver) deduceOverlap() bool {
+	constraints := s.constraints()
+	byCell := map[int][]int{}
+	for j, c := range constraints {
+		for _, i := range c.cells {
+			byCell[i] = append(byCell[i], j)
+		}
+	}
+	changed := false
+	for a := range constraints {
+		seen := map[int]bool{}
+		for _, i := range constraints[a].cells {
+			for _, b := range byCell[i] {
+				if b == a || seen[b] {
+					continue
+				}
+				seen[b] = true
+				changed = s.overlap(constraints[a], constraints[b]) || changed
+			}
+		}
+	}
+	return changed
+}
+
+// overlap bounds the mines in the shared cells of two constraints and
+// marks the cells that only one of them has when the bounds allow it
+func (s *solver) overlap(a, b constraint) bool {
+	inB := map[int]bool{}
+	for _, i := range b.cells {
+		inB[i] = true
+	}
+	onlyA, shared := []int{}, 0
+	for _, i := range a.cells {
+		if inB[i] {
+			shared++
+		} else {
+			onlyA = append(onlyA, i)
+		}
+	}
+	onlyB := len(b.cells) - shared
+	low := maxInt(0, maxInt(a.count-len(onlyA), b.count-onlyB))
+	high := minInt(shared, minInt(a.count, b.count))
+	if len(onlyA) == 0 || low > high {
+		return false
+	}
+	if a.count-high == len(onlyA) {
+		return s.mark(onlyA, true)
+	}
+	if a.count-low == 0 {
+		return s.mark(onlyA, false)
+	}
+	return false
+}
+
+func (s *solver) deduceTotal() bool {
+	remaining := s.b.mines
+	unknown := []int{}
+	for i := range s.open {
+		if s.mine[i] {
+			remaining--
+		} else if s.isUnknown(i) {
+			unknown = append(unknown, i)
+		}
+	}
+	if len(unknown) == 0 {
+		return false
+	}
+	if remaining == 0 {
+		return s.mark(unknown, false)
+	}
+	if remaining == len(unknown) {
+		return s.mark(unknown, true)
+	}
+	return false
+}
+
+// solvable simulates a game from the first opened cell in which only
+// cells that the solver proves safe are opened
+func (s *solver) solvable(x, y int) bool {
+	s.reveal(y*s.b.width + x)
+	return s.solve()
+}
+
+// solve opens the cells that are proven safe until nothing more can be
+// proven and reports whether all cells without a mine are open
+func (s *solver) solve() bool {
+	for {
+		s.deduce()
+		progress := false
+		for i := range s.safe {
+			if s.safe[i] {
+				s.reveal(i)
+				progress = true
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+	for i := range s.open {
+		if !s.open[i] && !s.b.cells[i/s.b.width][i%s.b.width].Mine {
+			return false
+		}
+	}
+	return true
+}
+
+// moveMine moves a random mine next to the open area that the solver is
+// stuck on to a random closed cell that is not next to the open area, so
+// that the solver can continue, and reports whether there was such a mine
+// and such a cell
+func (s *solver) moveMine(rng *rand.Rand) bool {
+	stuck, away, free := []int{}, []int{}, []int{}
+	for i := range s.open {
+		if !s.isUnknown(i) {
+			continue
+		}
+		border := false
+		for _, n := range s.neighbours[i] {
+			border = border || s.open[n]
+		}
+		mine := s.b.cells[i/s.b.width][i%s.b.width].Mine
+		if border && mine {
+			stuck = append(stuck, i)
+		} else if !border && !mine {
+			away = append(away, i)
+		} else if !mine {
+			free = append(free, i)
+		}
+	}
+	if len(away) == 0 {
+		away = free
+	}
+	if len(stuck) == 0 || len(away) == 0 {
+		return false
+	}
+	from, to := stuck[rng.Intn(len(stuck))], away[rng.Intn(len(away))]
+	s.b.cells[from/s.b.width][from%s.b.width].Mine = false
+	s.b.cells[to/s.b.width][to%s.b.width].Mine = true
+	for _, n := range s.neighbours[from] {
+		s.b.cells[n/s.b.width][n%s.b.width].Number--
+	}
+	for _, n := range s.neighbours[to] {
+		s.b.cells[n/s.b.width][n%s.b.width].Number++
+	}
+	// the numbers changed, so what was proven is forgotten, and the open
+	// cells that lost their last mine open their neighbours like the board
+	for i := range s.mine {
+		s.mine[i], s.safe[i] = false, false
+	}
+	for _, n := range s.neighbours[from] {
+		if s.open[n] && s.number(n) == 0 {
+			for _, m := range s.neighbours[n] {
+				s.reveal(m)
+			}
+		}
+	}
+	return true
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,23 @@
+package board
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNoGuessExpert(t *testing.T) {
+	start := time.Now()
+	for seed := int64(1); seed <= 20; seed++ {
+		b := New(Config{Width: 30, Height: 16, Mines: 99, Seed: seed, Placement: NoGuess})
+		b.Open(15, 8)
+		if b.Guessing() {
+			t.Fatalf("seed %d: needs a guess", seed)
+		}
+		if !newSolver(b).solvable(15, 8) {
+			t.Fatalf("seed %d: is not solved from the first open", seed)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("20 boards took %v", elapsed)
+	}
+}
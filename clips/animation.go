@@ -0,0 +1,118 @@
+package clips
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// animation holds the playback state of the frames of a clip
+type animation struct {
+	playing       bool
+	loop          bool
+	pingPong      bool
+	direction     int
+	frameTicks    int
+	frameDuration time.Duration
+	ticks         int
+	onComplete    func()
+}
+
+// Play plays the frames of the clip once from the first frame
+func (c *Clip) Play() {
+	c.start(false)
+}
+
+// Loop plays the frames of the clip from the first frame until it is stopped
+func (c *Clip) Loop() {
+	c.start(true)
+}
+
+func (c *Clip) start(loop bool) {
+	c.frame = 0
+	c.animation.playing = true
+	c.animation.loop = loop
+	c.animation.direction = 1
+	c.animation.ticks = 0
+}
+
+// Stop stops playing the frames and keeps the current frame
+func (c *Clip) Stop() {
+	c.animation.playing = false
+}
+
+// IsPlaying reports whether the frames of the clip are playing
+func (c *Clip) IsPlaying() bool {
+	return c.animation.playing
+}
+
+// SetFrameTicks sets the number of ticks that each frame is shown
+func (c *Clip) SetFrameTicks(ticks int) {
+	c.animation.frameTicks = ticks
+	c.animation.frameDuration = 0
+}
+
+// SetFrameDuration sets the time that each frame is shown, it is rounded
+// to whole ticks
+func (c *Clip) SetFrameDuration(d time.Duration) {
+	c.animation.frameDuration = d
+	c.animation.frameTicks = 0
+}
+
+// SetPingPong sets whether the frames play forward and then backward
+func (c *Clip) SetPingPong(pingPong bool) {
+	c.animation.pingPong = pingPong
+}
+
+// OnComplete sets the handler that is called when a Play has shown the
+// last frame (or the first frame again in ping-pong mode)
+func (c *Clip) OnComplete(handler func()) {
+	c.animation.onComplete = handler
+}
+
+func (a *animation) ticksPerFrame() int {
+	ticks := a.frameTicks
+	if a.frameDuration > 0 {
+		ticks = int(a.frameDuration * time.Duration(ebiten.TPS()) / time.Second)
+	}
+	if ticks < 1 {
+		ticks = 1
+	}
+	return ticks
+}
+
+// Animate advances the frames of a playing clip and its tweens by a tick
+func (c *Clip) Animate() {
+	c.tween()
+	a := &c.animation
+	if !a.playing {
+		return
+	}
+	a.ticks++
+	if a.ticks < a.ticksPerFrame() {
+		return
+	}
+	a.ticks = 0
+	next := c.frame + a.direction
+	if next >= 0 && next < len(c.frames) {
+		c.frame = next
+		return
+	}
+	if a.pingPong && a.direction > 0 && len(c.frames) > 1 {
+		a.direction = -1
+		c.frame--
+		return
+	}
+	if a.loop {
+		a.direction = 1
+		c.frame = 0
+		if a.pingPong && len(c.frames) > 1 {
+			c.frame = 1
+		}
+		return
+	}
+	a.playing = false
+	if a.onComplete != nil {
+		a.onComplete()
+	}
+}
@@ -2,13 +2,32 @@ package clips
 
 import (
 	"image"
+	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/mevdschee/ebiten-mines/mouse"
 	"github.com/mevdschee/ebiten-mines/sprites"
 	"github.com/mevdschee/ebiten-mines/touch"
 )
 
+var holdDuration = 500 * time.Millisecond
+
+// SetHoldDuration sets how long a press must be held to become a long press
+func SetHoldDuration(d time.Duration) {
+	holdDuration = d
+}
+
+func holdTicks() int {
+	ticks := int(holdDuration * time.Duration(ebiten.TPS()) / time.Second)
+	if ticks < 1 {
+		ticks = 1
+	}
+	return ticks
+}
+
 // Clip is a set of frames
 type Clip struct {
 	name             string
@@ -16,19 +35,33 @@ type Clip struct {
 	width, height    int
 	frame            int
 	frames           []*ebiten.Image
+	shared           bool
+	text             string
+	scaled           *sprites.Sprite
 	onPress          func()
 	onLongPress      func()
 	onRelease        func()
 	onReleaseOutside func()
+	onChordPress     func()
+	onChordRelease   func()
+	animation        animation
+	transform        transform
+	tweens           []*Tween
 }
 
-// ClipJSON is a clip in JSON
+// ClipJSON is a clip in JSON, Play is "once" or "loop" to play the frames
+// from the start, showing each frame for Ticks ticks or Duration ms
 type ClipJSON struct {
 	Name          string
 	Sprite        string
+	Text          string
 	Repeat        string
 	X, Y          string
 	Width, Height string
+	Play          string
+	PingPong      bool
+	Ticks         string
+	Duration      string
 }
 
 // GetName gets the name of the clip
@@ -54,18 +87,35 @@ func New(sprite *sprites.Sprite, name string, x, y int) *Clip {
 	}
 
 	return &Clip{
-		name:   name,
-		x:      x,
-		y:      y,
-		width:  srcWidth,
-		height: srcHeight,
-		frame:  0,
-		frames: frames,
+		name:      name,
+		x:         x,
+		y:         y,
+		width:     srcWidth,
+		height:    srcHeight,
+		frame:     0,
+		frames:    frames,
+		shared:    true,
+		transform: newTransform(),
 	}
 }
 
 // NewScaled creates a new 9 slice scaled sprite based clip
 func NewScaled(sprite *sprites.Sprite, name string, x, y, width, height int) *Clip {
+	return &Clip{
+		name:      name,
+		x:         x,
+		y:         y,
+		width:     width,
+		height:    height,
+		frame:     0,
+		frames:    []*ebiten.Image{scaledFrame(sprite, width, height)},
+		scaled:    sprite,
+		transform: newTransform(),
+	}
+}
+
+// scaledFrame draws the 9 slices of a sprite scaled to a size
+func scaledFrame(sprite *sprites.Sprite, width, height int) *ebiten.Image {
 	frame0 := ebiten.NewImage(width, height)
 
 	srcY := sprite.Y
@@ -99,25 +149,104 @@ func NewScaled(sprite *sprites.Sprite, name string, x, y, width, height int) *Cl
 		dstY += dstHeight
 	}
 
-	return &Clip{
-		name:   name,
-		x:      x,
-		y:      y,
-		width:  width,
-		height: height,
-		frame:  0,
-		frames: []*ebiten.Image{frame0},
+	return frame0
+}
+
+// SetSize resizes a 9 slice scaled clip, other clips keep their size
+func (c *Clip) SetSize(width, height int) {
+	if c.scaled == nil || (width == c.width && height == c.height) {
+		return
+	}
+	c.frames[0].Dispose()
+	c.frames[0] = scaledFrame(c.scaled, width, height)
+	c.width, c.height = width, height
+}
+
+// NewText creates a new text based clip
+func NewText(name string, x, y int, text string) *Clip {
+	c := &Clip{
+		name:      name,
+		x:         x,
+		y:         y,
+		frame:     0,
+		frames:    []*ebiten.Image{},
+		transform: newTransform(),
 	}
+	c.SetText(text)
+	return c
+}
+
+// SetText renders the text as the only frame of the clip
+func (c *Clip) SetText(text string) {
+	if text == c.text && len(c.frames) > 0 {
+		return
+	}
+	for _, frame := range c.frames {
+		frame.Dispose()
+	}
+	c.text = text
+	c.frame = 0
+	c.frames = []*ebiten.Image{}
+	lines := strings.Split(text, "\n")
+	columns := 0
+	for _, line := range lines {
+		if len(line) > columns {
+			columns = len(line)
+		}
+	}
+	c.width, c.height = columns*6+1, len(lines)*16
+	if columns == 0 {
+		return
+	}
+	white := ebiten.NewImage(c.width, c.height)
+	ebitenutil.DebugPrint(white, text)
+	frame0 := ebiten.NewImage(c.width, c.height)
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.Scale(0, 0, 0, 1)
+	frame0.DrawImage(white, op)
+	white.Dispose()
+	c.frames = append(c.frames, frame0)
+}
+
+// Dispose releases the frames that the clip rendered, the frames of a sprite
+// based clip are part of the sprite and are kept
+func (c *Clip) Dispose() {
+	if c.shared {
+		return
+	}
+	for _, frame := range c.frames {
+		frame.Dispose()
+	}
+	c.frames = []*ebiten.Image{}
+}
+
+// GetText gets the text of a text based clip
+func (c *Clip) GetText() string {
+	return c.text
 }
 
 // Draw draws the clip
 func (c *Clip) Draw(screen *ebiten.Image) {
+	if len(c.frames) == 0 {
+		return
+	}
 	img := c.frames[c.frame]
+	t := c.transform
 	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(c.x), float64(c.y))
+	anchorX, anchorY := t.anchorX*float64(c.width), t.anchorY*float64(c.height)
+	op.GeoM.Translate(-anchorX, -anchorY)
+	op.GeoM.Scale(t.scaleX, t.scaleY)
+	op.GeoM.Rotate(t.rotation)
+	op.GeoM.Translate(float64(c.x)+anchorX, float64(c.y)+anchorY)
+	op.ColorScale.ScaleAlpha(float32(t.alpha))
 	screen.DrawImage(img, op)
 }
 
+// SetPosition moves the clip to a position
+func (c *Clip) SetPosition(x, y int) {
+	c.x, c.y = x, y
+}
+
 // GotoFrame goes to a frame of the clip
 func (c *Clip) GotoFrame(frame int) {
 	if frame >= 0 && frame < len(c.frames) {
@@ -145,6 +274,16 @@ func (c *Clip) OnReleaseOutside(handler func()) {
 	c.onReleaseOutside = handler
 }
 
+// OnChordPress sets the handler function for pressing both buttons or the middle button
+func (c *Clip) OnChordPress(handler func()) {
+	c.onChordPress = handler
+}
+
+// OnChordRelease sets the handler function for releasing a chord
+func (c *Clip) OnChordRelease(handler func()) {
+	c.onChordRelease = handler
+}
+
 // IsHovered returns whether or not the cursor is hovering the clip
 func (c *Clip) IsHovered() bool {
 	cursorX, cursorY := ebiten.CursorPosition()
@@ -174,28 +313,38 @@ func (c *Clip) Update() (err error) {
 	hover := c.IsHovered()
 
 	if c.onPress != nil {
-		if hover && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if hover && mouse.IsLeftJustPressed() {
 			c.onPress()
 		}
 	}
 	if c.onLongPress != nil {
-		if hover && inpututil.MouseButtonPressDuration(ebiten.MouseButtonLeft) == ebiten.TPS()/2 {
+		if hover && mouse.LeftPressDuration() == holdTicks() {
 			c.onLongPress()
 		}
-		if hover && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		if hover && mouse.IsRightJustPressed() {
 			c.onLongPress()
 		}
 	}
 	if c.onRelease != nil {
-		if hover && inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		if hover && mouse.IsLeftJustReleased() {
 			c.onRelease()
 		}
 	}
 	if c.onReleaseOutside != nil {
-		if !hover && inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		if !hover && (mouse.IsLeftJustReleased() || mouse.IsChordJustReleased()) {
 			c.onReleaseOutside()
 		}
 	}
+	if c.onChordPress != nil {
+		if hover && mouse.IsChordJustPressed() {
+			c.onChordPress()
+		}
+	}
+	if c.onChordRelease != nil {
+		if hover && mouse.IsChordJustReleased() {
+			c.onChordRelease()
+		}
+	}
 	touchIDs := touch.GetTouchIDs()
 	for i := 0; i < len(touchIDs); i++ {
 		touchID := touchIDs[i]
@@ -207,7 +356,7 @@ func (c *Clip) Update() (err error) {
 			}
 		}
 		if c.onLongPress != nil {
-			if touched && inpututil.TouchPressDuration(touchID) == ebiten.TPS()/2 {
+			if touched && inpututil.TouchPressDuration(touchID) == holdTicks() {
 				c.onLongPress()
 			}
 		}
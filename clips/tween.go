@@ -0,0 +1,223 @@
+package clips
+
+import (
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Property is a property of a clip that can be tweened
+type Property int
+
+const (
+	// X is the horizontal position in pixels
+	X Property = iota
+	// Y is the vertical position in pixels
+	Y
+	// Alpha is the opacity from 0 to 1
+	Alpha
+	// ScaleX is the horizontal scale factor
+	ScaleX
+	// ScaleY is the vertical scale factor
+	ScaleY
+	// Rotation is the clockwise rotation in radians
+	Rotation
+)
+
+// Props holds values for properties of a clip
+type Props map[Property]float64
+
+// Easing maps the progress of a tween from 0 to 1 onto a curve
+type Easing func(t float64) float64
+
+// Standard easing curves
+var (
+	Linear         Easing = func(t float64) float64 { return t }
+	EaseInQuad     Easing = func(t float64) float64 { return t * t }
+	EaseOutQuad    Easing = func(t float64) float64 { return t * (2 - t) }
+	EaseInOutQuad  Easing = func(t float64) float64 { return inOut(EaseInQuad, t) }
+	EaseInCubic    Easing = func(t float64) float64 { return t * t * t }
+	EaseOutCubic   Easing = func(t float64) float64 { return 1 - math.Pow(1-t, 3) }
+	EaseInOutCubic Easing = func(t float64) float64 { return inOut(EaseInCubic, t) }
+	EaseInOutSine  Easing = func(t float64) float64 { return (1 - math.Cos(math.Pi*t)) / 2 }
+	EaseOutBack    Easing = func(t float64) float64 {
+		const c = 1.70158
+		return 1 + (c+1)*math.Pow(t-1, 3) + c*math.Pow(t-1, 2)
+	}
+	EaseOutBounce Easing = func(t float64) float64 {
+		switch {
+		case t < 1/2.75:
+			return 7.5625 * t * t
+		case t < 2/2.75:
+			t -= 1.5 / 2.75
+			return 7.5625*t*t + 0.75
+		case t < 2.5/2.75:
+			t -= 2.25 / 2.75
+			return 7.5625*t*t + 0.9375
+		}
+		t -= 2.625 / 2.75
+		return 7.5625*t*t + 0.984375
+	}
+)
+
+// inOut makes an in-out curve of an in curve
+func inOut(in Easing, t float64) float64 {
+	if t < 0.5 {
+		return in(t*2) / 2
+	}
+	return 1 - in((1-t)*2)/2
+}
+
+// Tween changes properties of a clip from their current values to target
+// values over a number of ticks
+type Tween struct {
+	from       Props
+	to         Props
+	ticks      int
+	tick       int
+	easing     Easing
+	onComplete func()
+}
+
+// OnComplete sets the handler that is called when the tween is done
+func (t *Tween) OnComplete(handler func()) {
+	t.onComplete = handler
+}
+
+// transform holds the properties of a clip that are used when drawing
+type transform struct {
+	alpha            float64
+	scaleX, scaleY   float64
+	rotation         float64
+	anchorX, anchorY float64
+}
+
+func newTransform() transform {
+	return transform{alpha: 1, scaleX: 1, scaleY: 1}
+}
+
+// SetAlpha sets the opacity of the clip from 0 to 1
+func (c *Clip) SetAlpha(alpha float64) {
+	c.transform.alpha = alpha
+}
+
+// SetScale sets the scale factors of the clip
+func (c *Clip) SetScale(x, y float64) {
+	c.transform.scaleX, c.transform.scaleY = x, y
+}
+
+// SetRotation sets the clockwise rotation of the clip in radians
+func (c *Clip) SetRotation(rotation float64) {
+	c.transform.rotation = rotation
+}
+
+// SetAnchor sets the point that the clip scales and rotates around, as a
+// fraction of its size, 0.5, 0.5 is the center
+func (c *Clip) SetAnchor(x, y float64) {
+	c.transform.anchorX, c.transform.anchorY = x, y
+}
+
+// get gets the value of a property
+func (c *Clip) get(p Property) float64 {
+	switch p {
+	case X:
+		return float64(c.x)
+	case Y:
+		return float64(c.y)
+	case Alpha:
+		return c.transform.alpha
+	case ScaleX:
+		return c.transform.scaleX
+	case ScaleY:
+		return c.transform.scaleY
+	case Rotation:
+		return c.transform.rotation
+	}
+	return 0
+}
+
+// set sets the value of a property
+func (c *Clip) set(p Property, value float64) {
+	switch p {
+	case X:
+		c.x = int(math.Round(value))
+	case Y:
+		c.y = int(math.Round(value))
+	case Alpha:
+		c.transform.alpha = value
+	case ScaleX:
+		c.transform.scaleX = value
+	case ScaleY:
+		c.transform.scaleY = value
+	case Rotation:
+		c.transform.rotation = value
+	}
+}
+
+// TweenTo changes properties from their current values to the given values
+// over a duration, it replaces running tweens of the same properties, the
+// props are copied so they can be reused for other clips
+func (c *Clip) TweenTo(props Props, duration time.Duration, easing Easing) *Tween {
+	t := &Tween{
+		from:   Props{},
+		to:     Props{},
+		ticks:  int(duration * time.Duration(ebiten.TPS()) / time.Second),
+		easing: easing,
+	}
+	if t.easing == nil {
+		t.easing = Linear
+	}
+	for p, value := range props {
+		t.from[p] = c.get(p)
+		t.to[p] = value
+	}
+	tweens := []*Tween{}
+	for _, other := range c.tweens {
+		for p := range props {
+			delete(other.to, p)
+		}
+		if len(other.to) > 0 {
+			tweens = append(tweens, other)
+		}
+	}
+	c.tweens = append(tweens, t)
+	return t
+}
+
+// IsTweening reports whether any property of the clip is being tweened
+func (c *Clip) IsTweening() bool {
+	return len(c.tweens) > 0
+}
+
+// StopTweens stops all tweens of the clip at their current values
+func (c *Clip) StopTweens() {
+	c.tweens = nil
+}
+
+// tween advances the tweens of the clip by a tick
+func (c *Clip) tween() {
+	tweens := []*Tween{}
+	done := []*Tween{}
+	for _, t := range c.tweens {
+		t.tick++
+		progress := 1.0
+		if t.tick < t.ticks {
+			progress = t.easing(float64(t.tick) / float64(t.ticks))
+		}
+		for p, to := range t.to {
+			c.set(p, t.from[p]+(to-t.from[p])*progress)
+		}
+		if t.tick < t.ticks {
+			tweens = append(tweens, t)
+		} else {
+			done = append(done, t)
+		}
+	}
+	c.tweens = tweens
+	for _, t := range done {
+		if t.onComplete != nil {
+			t.onComplete()
+		}
+	}
+}
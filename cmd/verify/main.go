@@ -0,0 +1,194 @@
+// Command verify checks replay files by playing them on the rules engine
+// without a display, for example to accept times for a leaderboard:
+//
+//	go run ./cmd/verify replays/20240407-120000.jsonl
+//
+// It prints the verified time and 3BV of every replay and exits with status
+// 1 when any replay has illegal actions, does not end in a win, used hints,
+// assistants or undo, or claims a result (mines, time, 3BV) that differs from
+// what the rules produce. Only boards that the game offers and replays that
+// are recorded at the ticks per second of the game (-tps) are accepted, and
+// the timing must be humanly possible: every open and chord follows a press
+// on its cell at an earlier tick (except the first open, that starts the
+// clock), moves are at least 30ms apart and the 3BV/s is at most 15.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/replay"
+)
+
+const (
+	// minGap is the shortest time between two moves
+	minGap = 30 * time.Millisecond
+	// maxThreeBVPerSecond is above the fastest games that humans play
+	maxThreeBVPerSecond = 15
+)
+
+var tps = flag.Int("tps", replay.DefaultTPS, "ticks per second that replays must be recorded at")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: verify file.jsonl...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	failed := false
+	for _, filename := range flag.Args() {
+		result, err := verify(filename)
+		if err != nil {
+			fmt.Printf("%s: FAIL: %v\n", filename, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s: OK: %s\n", filename, result)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// verify plays a replay file and describes the verified result
+func verify(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	r, err := replay.Parse(data)
+	if err != nil {
+		return "", err
+	}
+	if r.Header.TPS != *tps {
+		return "", fmt.Errorf("Replay is recorded at %d ticks per second instead of %d", r.Header.TPS, *tps)
+	}
+	c := r.Header.Config
+	if c.Width < board.MinWidth || c.Width > board.MaxWidth || c.Height < board.MinHeight || c.Height > board.MaxHeight {
+		return "", fmt.Errorf("Size %dx%d is not in range %d-%dx%d-%d", c.Width, c.Height, board.MinWidth, board.MaxWidth, board.MinHeight, board.MaxHeight)
+	}
+	if len(r.Events) == 0 || r.Events[len(r.Events)-1].Action != replay.End {
+		return "", fmt.Errorf("Replay has no end")
+	}
+	p, err := replay.NewPlayer(r)
+	if err != nil {
+		return "", err
+	}
+	if err := checkInput(r); err != nil {
+		return "", err
+	}
+	if err := p.Run(); err != nil {
+		return "", err
+	}
+	b := p.Board()
+	end := r.Events[len(r.Events)-1]
+	if b.State() != board.Won {
+		return "", fmt.Errorf("Game is %s instead of won", b.State())
+	}
+	if end.Result != b.State() {
+		return "", fmt.Errorf("Result is %s but the game is %s", end.Result, b.State())
+	}
+	if b.Hints() > 0 {
+		return "", fmt.Errorf("Game used %d hints", b.Hints())
+	}
+	if b.Undos() > 0 {
+		return "", fmt.Errorf("Game undid %d moves", b.Undos())
+	}
+	if b.Assists() > 0 {
+		return "", fmt.Errorf("Game used assistants on %d cells", b.Assists())
+	}
+	if err := checkMines(b, end.Mines); err != nil {
+		return "", err
+	}
+	if end.Tick != b.Ticks() {
+		return "", fmt.Errorf("End is at tick %d but the game ended at tick %d", end.Tick, b.Ticks())
+	}
+	m := b.Metrics()
+	if end.Elapsed != m.Elapsed.Milliseconds() {
+		return "", fmt.Errorf("Time is %dms but %d ticks at %d per second take %dms", end.Elapsed, b.Ticks(), r.Header.TPS, m.Elapsed.Milliseconds())
+	}
+	if end.ThreeBV != m.ThreeBV {
+		return "", fmt.Errorf("3BV is %d but the board has %d", end.ThreeBV, m.ThreeBV)
+	}
+	// the first click starts the clock, so the other clicks must fit in the time
+	if float64(m.ThreeBV-1) > maxThreeBVPerSecond*m.Elapsed.Seconds() {
+		return "", fmt.Errorf("3BV %d in %dms is faster than %d 3BV/s", m.ThreeBV, m.Elapsed.Milliseconds(), maxThreeBVPerSecond)
+	}
+	ms := m.Elapsed.Milliseconds()
+	return fmt.Sprintf("%s won %dx%d/%d in %d.%03ds on %s, 3BV %d, %.2f 3BV/s, %d%% efficiency",
+		r.Header.Name, b.Width(), b.Height(), b.Mines(), ms/1000, ms%1000, r.Header.Date.Format(time.RFC3339),
+		m.ThreeBV, m.ThreeBVPerSecond(), m.Efficiency()), nil
+}
+
+// checkInput checks that the moves are made the way the game records them:
+// every open follows a press on the same cell at an earlier tick, except the
+// first open that starts the clock, a chord does too unless it has no press
+// (a right click), and the moves after the first open are at least minGap
+// apart
+func checkInput(r *replay.Replay) error {
+	tick := time.Second / time.Duration(r.Header.TPS)
+	gap := int((minGap + tick - 1) / tick)
+	pressed := false
+	press := replay.Event{}
+	started := false
+	last := 0
+	for i, e := range r.Events {
+		switch e.Action {
+		case replay.Press, replay.ChordPress:
+			pressed, press = true, e
+		case replay.Open, replay.Chord:
+			onPress := pressed && press.X == e.X && press.Y == e.Y
+			if !onPress && e.Action == replay.Chord {
+				// a right click chords without a press
+				break
+			}
+			if !onPress {
+				return fmt.Errorf("Event %d is %s on %d,%d without a press on it", i+1, e.Action, e.X, e.Y)
+			}
+			if started && press.Tick >= e.Tick {
+				return fmt.Errorf("Event %d is %s on %d,%d in the tick of its press", i+1, e.Action, e.X, e.Y)
+			}
+			pressed = false
+		}
+		switch e.Action {
+		case replay.Open, replay.Flag, replay.Chord:
+			if started && e.Tick-last < gap {
+				return fmt.Errorf("Event %d is %s on %d,%d only %dms after the previous move", i+1, e.Action, e.X, e.Y, (time.Duration(e.Tick-last) * tick).Milliseconds())
+			}
+			started = started || e.Action == replay.Open
+			last = e.Tick
+		}
+	}
+	return nil
+}
+
+// checkMines compares the mines in the replay with the mines that the seed
+// placed on the board
+func checkMines(b *board.Board, mines []int) error {
+	claimed := map[int]bool{}
+	for _, i := range mines {
+		claimed[i] = true
+	}
+	count := 0
+	for y := 0; y < b.Height(); y++ {
+		for x := 0; x < b.Width(); x++ {
+			if b.Cell(x, y).Mine != claimed[y*b.Width()+x] {
+				return fmt.Errorf("Mines do not match the seed at %d,%d", x, y)
+			}
+			if b.Cell(x, y).Mine {
+				count++
+			}
+		}
+	}
+	if count != len(mines) {
+		return fmt.Errorf("Replay has %d mines but the board has %d", len(mines), count)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/replay"
+)
+
+// moves creates a replay at 30 ticks per second with the given events
+func moves(events ...replay.Event) *replay.Replay {
+	r := replay.New(board.Config{Width: 9, Height: 9, Mines: 10, Seed: 42}, 30, "test")
+	r.Events = events
+	return r
+}
+
+func TestCheckInputChords(t *testing.T) {
+	start := []replay.Event{
+		{Tick: 0, Action: replay.Press, X: 4, Y: 4},
+		{Tick: 0, Action: replay.Release, X: 4, Y: 4},
+		{Tick: 0, Action: replay.Open, X: 4, Y: 4},
+	}
+	rightClick := append(start[:3:3],
+		replay.Event{Tick: 10, Action: replay.Chord, X: 3, Y: 3},
+	)
+	if err := checkInput(moves(rightClick...)); err != nil {
+		t.Fatalf("right click chord: %v", err)
+	}
+	longPress := append(start[:3:3],
+		replay.Event{Tick: 10, Action: replay.Press, X: 3, Y: 3},
+		replay.Event{Tick: 25, Action: replay.Chord, X: 3, Y: 3},
+	)
+	if err := checkInput(moves(longPress...)); err != nil {
+		t.Fatalf("long press chord: %v", err)
+	}
+	bothButtons := append(start[:3:3],
+		replay.Event{Tick: 10, Action: replay.ChordPress, X: 3, Y: 3},
+		replay.Event{Tick: 12, Action: replay.Release, X: 3, Y: 3},
+		replay.Event{Tick: 12, Action: replay.Chord, X: 3, Y: 3},
+	)
+	if err := checkInput(moves(bothButtons...)); err != nil {
+		t.Fatalf("chord with both buttons: %v", err)
+	}
+	samePress := append(start[:3:3],
+		replay.Event{Tick: 10, Action: replay.ChordPress, X: 3, Y: 3},
+		replay.Event{Tick: 10, Action: replay.Chord, X: 3, Y: 3},
+	)
+	if err := checkInput(moves(samePress...)); err == nil {
+		t.Fatal("chord in the tick of its press is accepted")
+	}
+}
+
+func TestCheckInputOpens(t *testing.T) {
+	noPress := []replay.Event{
+		{Tick: 0, Action: replay.Open, X: 4, Y: 4},
+	}
+	if err := checkInput(moves(noPress...)); err == nil {
+		t.Fatal("open without a press is accepted")
+	}
+	otherCell := []replay.Event{
+		{Tick: 0, Action: replay.Press, X: 4, Y: 4},
+		{Tick: 0, Action: replay.Open, X: 4, Y: 4},
+		{Tick: 10, Action: replay.Press, X: 0, Y: 0},
+		{Tick: 12, Action: replay.Open, X: 8, Y: 8},
+	}
+	if err := checkInput(moves(otherCell...)); err == nil {
+		t.Fatal("open on another cell than the press is accepted")
+	}
+	tooFast := []replay.Event{
+		{Tick: 0, Action: replay.Press, X: 4, Y: 4},
+		{Tick: 0, Action: replay.Open, X: 4, Y: 4},
+		{Tick: 0, Action: replay.Flag, X: 0, Y: 0},
+	}
+	if err := checkInput(moves(tooFast...)); err == nil {
+		t.Fatal("moves in the same tick are accepted")
+	}
+}
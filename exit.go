@@ -0,0 +1,11 @@
+//go:build !js
+
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// onExit calls the handler when the window is closed
+func onExit(handler func()) {
+	ebiten.SetWindowClosingHandled(true)
+	exitHandler = handler
+}
@@ -0,0 +1,13 @@
+//go:build js
+
+package main
+
+import "syscall/js"
+
+// onExit calls the handler when the page is closed or reloaded
+func onExit(handler func()) {
+	js.Global().Call("addEventListener", "beforeunload", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		handler()
+		return nil
+	}))
+}
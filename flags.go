@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/replay"
+)
+
+func parseFlags(arguments []string) (config, error) {
+	c := config{scale: 1, tps: replay.DefaultTPS, holding: 500, name: os.Getenv("USER"), resume: true}.withDifficulty(difficulties[0])
+	if c.name == "" {
+		c.name = "player"
+	}
+	names := []string{}
+	for _, d := range difficulties {
+		names = append(names, d.name)
+	}
+	flags := flag.NewFlagSet("ebiten-mines", flag.ExitOnError)
+	preset := flags.String("preset", "", "board preset: "+strings.Join(names, ", "))
+	flags.IntVar(&c.width, "width", c.width, fmt.Sprintf("board width in tiles (%d-%d)", minWidth, maxWidth))
+	flags.IntVar(&c.height, "height", c.height, fmt.Sprintf("board height in tiles (%d-%d)", minHeight, maxHeight))
+	flags.IntVar(&c.bombs, "mines", c.bombs, "number of mines")
+	placement := flags.String("placement", c.placement.String(), "mine placement: classic (first click is safe), opening (first click opens an area) or noguess (opening that never needs a guess, at most 1 mine per 4 tiles)")
+	flags.BoolVar(&c.questions, "questions", c.questions, "right-click cycles from flag to question mark to blank")
+	flags.BoolVar(&c.practice, "practice", c.practice, "practice mode, moves can be undone (also after hitting a mine), games with undone moves are not recorded and the others are kept apart in the statistics")
+	flags.BoolVar(&c.autoFlag, "autoflag", c.autoFlag, "flag tiles that are certainly mines after every move")
+	flags.BoolVar(&c.autoOpen, "autoopen", c.autoOpen, "open tiles next to numbers that are satisfied by certain mines after every move")
+	flags.StringVar(&c.name, "name", c.name, "player name for the best times")
+	flags.BoolVar(&c.resume, "resume", c.resume, "resume the saved game, use -resume=false to start a new game")
+	flags.StringVar(&c.replay, "replay", c.replay, "play back a replay file instead of playing a game")
+	flags.IntVar(&c.scale, "scale", c.scale, "window scale factor (1-8)")
+	flags.Int64Var(&c.seed, "seed", c.seed, "seed for the mine placement, 0 picks a new seed every game")
+	flags.IntVar(&c.tps, "tps", c.tps, "ticks per second (10-240)")
+	flags.IntVar(&c.holding, "holding", c.holding, "milliseconds to hold a press before it flags (100-5000)")
+	flags.Parse(arguments)
+	if flags.NArg() > 0 {
+		return c, fmt.Errorf("unexpected argument '%s'", flags.Arg(0))
+	}
+	if *preset != "" {
+		sizeFlags := []string{}
+		flags.Visit(func(f *flag.Flag) {
+			if f.Name == "width" || f.Name == "height" || f.Name == "mines" {
+				sizeFlags = append(sizeFlags, "-"+f.Name)
+			}
+		})
+		if len(sizeFlags) > 0 {
+			return c, fmt.Errorf("-preset can not be combined with %s", strings.Join(sizeFlags, ", "))
+		}
+		found := false
+		for _, d := range difficulties {
+			if d.name == *preset {
+				c = c.withDifficulty(d)
+				found = true
+			}
+		}
+		if !found {
+			return c, fmt.Errorf("-preset '%s' is not one of: %s", *preset, strings.Join(names, ", "))
+		}
+	}
+	var err error
+	c.placement, err = board.ParsePlacement(*placement)
+	if err != nil {
+		return c, fmt.Errorf("-placement: %v", err)
+	}
+	if c.scale < 1 || c.scale > 8 {
+		return c, fmt.Errorf("-scale %d is not in range 1-8", c.scale)
+	}
+	if c.tps < 10 || c.tps > 240 {
+		return c, fmt.Errorf("-tps %d is not in range 10-240", c.tps)
+	}
+	if c.holding < 100 || c.holding > 5000 {
+		return c, fmt.Errorf("-holding %d is not in range 100-5000", c.holding)
+	}
+	if err = c.validate(); err != nil {
+		return c, fmt.Errorf("-width %d -height %d -mines %d -placement %s: %v", c.width, c.height, c.bombs, c.placement, err)
+	}
+	return c, nil
+}
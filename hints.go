@@ -0,0 +1,94 @@
+package main
+
+import (
+	"log"
+	"math"
+
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/replay"
+)
+
+// toggleHeatmap shows or hides the chance of a mine on every closed tile
+func (g *game) toggleHeatmap() {
+	if g.isOver() {
+		return
+	}
+	g.heatmap = !g.heatmap
+	g.updateHeatmap()
+}
+
+// updateHeatmap works out the chances again after the board changed
+func (g *game) updateHeatmap() {
+	if !g.heatmap {
+		return
+	}
+	g.record(replay.Heatmap, 0, 0)
+	p, err := g.board.Probabilities()
+	if err != nil {
+		log.Printf("could not work out the chances: %v\n", err)
+		g.heatmap = false
+		return
+	}
+	g.probabilities = p
+}
+
+// hint highlights a tile that is certainly safe or says there is none
+func (g *game) hint() {
+	if g.isOver() {
+		return
+	}
+	x, y, ok := g.board.Hint()
+	g.record(replay.Hint, x, y)
+	g.hinting = ok
+	g.hintX, g.hintY = x, y
+	g.message = ""
+	if !ok {
+		g.message = "No safe move"
+	}
+}
+
+func (g *game) setHeat() {
+	heat := g.getLayerClips("game", "heat", "heat")
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			frame := 0
+			if g.heatmap && !g.isOver() && !g.board.Cell(x, y).Open {
+				frame = 1 + int(math.Round(g.probabilities[y][x]*10))
+			}
+			if g.hinting && x == g.hintX && y == g.hintY {
+				frame = 1
+			}
+			heat[y*g.c.width+x].GotoFrame(frame)
+		}
+	}
+	g.getLayerClips("game", "heat", "message")[0].SetText(g.message)
+}
+
+// assist runs the enabled assistants after a move
+func (g *game) assist() {
+	if g.board.State() != board.Playing {
+		return
+	}
+	if g.c.autoFlag {
+		g.record(replay.AutoFlag, 0, 0)
+		g.board.AutoFlag()
+	}
+	if g.c.autoOpen {
+		g.record(replay.AutoOpen, 0, 0)
+		g.board.AutoOpen()
+	}
+}
+
+// toggleAssistant turns an assistant on or off and runs it right away
+func (g *game) toggleAssistant(enabled *bool, name string) {
+	*enabled = !*enabled
+	g.message = name + " off"
+	if *enabled {
+		g.message = name + " on"
+	}
+	if g.isOver() {
+		return
+	}
+	g.assist()
+	g.endMove()
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/mevdschee/ebiten-mines/board"
+)
+
+var cursorKeys = map[ebiten.Key][2]int{
+	ebiten.KeyArrowLeft:  {-1, 0},
+	ebiten.KeyArrowRight: {1, 0},
+	ebiten.KeyArrowUp:    {0, -1},
+	ebiten.KeyArrowDown:  {0, 1},
+	ebiten.KeyH:          {-1, 0},
+	ebiten.KeyL:          {1, 0},
+	ebiten.KeyK:          {0, -1},
+	ebiten.KeyJ:          {0, 1},
+}
+
+var (
+	pauseKeys    = []ebiten.Key{ebiten.KeyP, ebiten.KeyPause}
+	openKeys     = []ebiten.Key{ebiten.KeySpace, ebiten.KeyEnter}
+	flagKeys     = []ebiten.Key{ebiten.KeyF}
+	chordKeys    = []ebiten.Key{ebiten.KeyD}
+	heatmapKeys  = []ebiten.Key{ebiten.KeyM}
+	hintKeys     = []ebiten.Key{ebiten.KeyN}
+	autoFlagKeys = []ebiten.Key{ebiten.KeyA}
+	autoOpenKeys = []ebiten.Key{ebiten.KeyO}
+	undoKeys     = []ebiten.Key{ebiten.KeyU, ebiten.KeyBackspace}
+)
+
+// isKeyRepeated returns whether or not a held key should act in this tick
+func isKeyRepeated(key ebiten.Key) bool {
+	duration := inpututil.KeyPressDuration(key)
+	delay, interval := ebiten.TPS()/3, ebiten.TPS()/15
+	if interval < 1 {
+		interval = 1
+	}
+	return duration == 1 || (duration >= delay && (duration-delay)%interval == 0)
+}
+
+func isAnyKeyJustPressed(keys []ebiten.Key) bool {
+	for _, key := range keys {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAnyKeyJustReleased(keys []ebiten.Key) bool {
+	for _, key := range keys {
+		if inpututil.IsKeyJustReleased(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *game) handleKeys() {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		g.cursor = false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF2) {
+		g.restart()
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		g.saveGame()
+	}
+	if isAnyKeyJustPressed(pauseKeys) {
+		g.paused = !g.paused && g.board.State() == board.Playing
+		g.cancelPress(g.pressX, g.pressY)
+	}
+	if g.paused {
+		return
+	}
+	if isAnyKeyJustPressed(undoKeys) {
+		g.undo()
+	}
+	if isAnyKeyJustPressed(heatmapKeys) {
+		g.toggleHeatmap()
+	}
+	if isAnyKeyJustPressed(hintKeys) {
+		g.hint()
+	}
+	if isAnyKeyJustPressed(autoFlagKeys) {
+		g.toggleAssistant(&g.c.autoFlag, "Auto-flag")
+	}
+	if isAnyKeyJustPressed(autoOpenKeys) {
+		g.toggleAssistant(&g.c.autoOpen, "Auto-open")
+	}
+	for key, move := range cursorKeys {
+		if isKeyRepeated(key) {
+			if g.cursor {
+				g.cursorX = clamp(g.cursorX+move[0], 0, g.c.width-1)
+				g.cursorY = clamp(g.cursorY+move[1], 0, g.c.height-1)
+			}
+			g.cursor = true
+		}
+	}
+	x, y := g.cursorX, g.cursorY
+	if isAnyKeyJustPressed(openKeys) {
+		g.cursor = true
+		g.pressTile(x, y)
+	}
+	if isAnyKeyJustReleased(openKeys) {
+		g.releaseTile(x, y)
+	}
+	if isAnyKeyJustPressed(flagKeys) {
+		g.cursor = true
+		if !g.board.Cell(x, y).Open {
+			g.longPressTile(x, y)
+		}
+	}
+	if isAnyKeyJustPressed(chordKeys) {
+		g.cursor = true
+		if g.board.Cell(x, y).Open {
+			g.pressTile(x, y)
+		}
+	}
+	if isAnyKeyJustReleased(chordKeys) && g.pressing {
+		g.releaseTile(x, y)
+	}
+}
+
+func (g *game) setCursor() {
+	cursor := g.getClips("game", "cursor")[0]
+	cursor.SetPosition(12+g.cursorX*16, 55+g.cursorY*16)
+	if g.cursor {
+		cursor.GotoFrame(1)
+	} else {
+		cursor.GotoFrame(0)
+	}
+}
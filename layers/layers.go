@@ -2,8 +2,8 @@ package layers
 
 import (
 	"fmt"
+	"math"
 
-	"github.com/expr-lang/expr"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/mevdschee/ebiten-mines/clips"
 	"github.com/mevdschee/ebiten-mines/sprites"
@@ -11,14 +11,23 @@ import (
 
 // Layer is a set of layers
 type Layer struct {
-	name  string
-	clips []*clips.Clip
+	name    string
+	clips   []*clips.Clip
+	layouts []*layout
+	visible bool
+	alpha   float64
+	modal   bool
+	buffer  *ebiten.Image
 }
 
-// LayerJSON is a set of layers in JSON
+// LayerJSON is a set of layers in JSON, a layer is visible and opaque
+// unless Visible or Alpha says otherwise
 type LayerJSON struct {
-	Name  string
-	Clips []clips.ClipJSON
+	Name    string
+	Clips   []clips.ClipJSON
+	Visible *bool
+	Alpha   *float64
+	Modal   bool
 }
 
 // GetName gets the name of the scene
@@ -29,83 +38,135 @@ func (l *Layer) GetName() string {
 // New creates a new layer
 func New(name string) *Layer {
 	return &Layer{
-		name:  name,
-		clips: []*clips.Clip{},
+		name:    name,
+		clips:   []*clips.Clip{},
+		layouts: []*layout{},
+		visible: true,
+		alpha:   1,
 	}
 }
 
-func eval(expression string, parameters map[string]interface{}) (int, error) {
-	if len(expression) == 0 {
-		return 0, nil
-	}
-	prog, _ := expr.Compile(expression, expr.AsInt())
-	value, err := expr.Run(prog, parameters)
-	if err != nil {
-		return 0, err
-	}
-	return value.(int), nil
+// SetVisible shows or hides the layer, a hidden layer is not drawn and its
+// clips are not updated
+func (l *Layer) SetVisible(visible bool) {
+	l.visible = visible
+}
+
+// IsVisible reports whether the layer is shown
+func (l *Layer) IsVisible() bool {
+	return l.visible
+}
+
+// SetAlpha sets the opacity of the layer from 0 (transparent) to 1 (opaque)
+func (l *Layer) SetAlpha(alpha float64) {
+	l.alpha = math.Max(0, math.Min(1, alpha))
+}
+
+// GetAlpha gets the opacity of the layer
+func (l *Layer) GetAlpha() float64 {
+	return l.alpha
+}
+
+// SetModal makes the layer take all input while it is visible, the layers
+// below it are then not updated
+func (l *Layer) SetModal(modal bool) {
+	l.modal = modal
+}
+
+// IsModal reports whether the layer takes all input while it is visible
+func (l *Layer) IsModal() bool {
+	return l.modal
 }
 
-// FromJSON creates a new layer from JSON
+// FromJSON creates a new layer from JSON, it keeps the compiled
+// expressions of the clips so that SetParameters can lay them out again
 func FromJSON(spriteMap sprites.SpriteMap, layerJSON LayerJSON, parameters map[string]interface{}) (*Layer, error) {
-	layer := Layer{
-		name:  layerJSON.Name,
-		clips: []*clips.Clip{},
+	layer := New(layerJSON.Name)
+	if layerJSON.Visible != nil {
+		layer.SetVisible(*layerJSON.Visible)
 	}
+	if layerJSON.Alpha != nil {
+		layer.SetAlpha(*layerJSON.Alpha)
+	}
+	layer.SetModal(layerJSON.Modal)
 	for _, clipJSON := range layerJSON.Clips {
-		sprite, ok := spriteMap[clipJSON.Sprite]
-		if !ok {
-			return nil, fmt.Errorf("Could not find sprite '%s' for clip with name '%s'", clipJSON.Sprite, clipJSON.Name)
-		}
-		repeat, err := eval(clipJSON.Repeat, parameters)
+		l, err := newLayout(spriteMap, clipJSON)
 		if err != nil {
-			return nil, fmt.Errorf("Repeat in '%s': %v", clipJSON.Repeat, err)
+			return nil, err
 		}
-		if repeat == 0 {
-			repeat = 1
-		}
-		for i := 0; i < repeat; i++ {
-			parameters["i"] = i
-			x, err := eval(clipJSON.X, parameters)
-			if err != nil {
-				return nil, fmt.Errorf("X in '%s': %v", clipJSON.X, err)
-			}
-			y, err := eval(clipJSON.Y, parameters)
-			if err != nil {
-				return nil, fmt.Errorf("Y in '%s': %v", clipJSON.Y, err)
-			}
-			width, err := eval(clipJSON.Width, parameters)
-			if err != nil {
-				return nil, fmt.Errorf("Width in '%s': %v", clipJSON.Width, err)
-			}
-			height, err := eval(clipJSON.Height, parameters)
-			if err != nil {
-				return nil, fmt.Errorf("Height in '%s': %v", clipJSON.Height, err)
-			}
-			if width == 0 {
-				layer.Add(clips.New(sprite, clipJSON.Name, x, y))
-			} else {
-				layer.Add(clips.NewScaled(sprite, clipJSON.Name, x, y, width, height))
-			}
+		layer.layouts = append(layer.layouts, l)
+	}
+	if err := layer.SetParameters(parameters); err != nil {
+		return nil, err
+	}
+	return layer, nil
+}
+
+// SetParameters lays out the clips from JSON again using new parameters,
+// clips that remain keep their handlers and state, clips of a repeat that
+// grew are created and clips of a repeat that shrunk are removed
+func (l *Layer) SetParameters(parameters map[string]interface{}) error {
+	env := map[string]interface{}{}
+	for name, value := range parameters {
+		env[name] = value
+	}
+	all := []*clips.Clip{}
+	for _, layout := range l.layouts {
+		if err := layout.update(env); err != nil {
+			return err
 		}
+		all = append(all, layout.clips...)
 	}
-	return &layer, nil
+	l.clips = all
+	return nil
 }
 
-// Add adds a layers to the scene
+// Animate advances the animations of the clips by a tick
+func (l *Layer) Animate() {
+	for _, clip := range l.clips {
+		clip.Animate()
+	}
+}
+
+// Add adds a clip to the layer, it keeps its position on SetParameters
 func (l *Layer) Add(clip *clips.Clip) {
 	l.clips = append(l.clips, clip)
+	l.layouts = append(l.layouts, &layout{clips: []*clips.Clip{clip}})
 }
 
-// Draw draws the layer
+// Draw draws the layer, a translucent layer is drawn offscreen first so
+// that its clips do not show through each other
 func (l *Layer) Draw(screen *ebiten.Image) {
+	if !l.visible || l.alpha == 0 {
+		return
+	}
+	if l.alpha == 1 {
+		for _, clip := range l.clips {
+			clip.Draw(screen)
+		}
+		return
+	}
+	size := screen.Bounds().Size()
+	if l.buffer == nil || l.buffer.Bounds().Size() != size {
+		if l.buffer != nil {
+			l.buffer.Dispose()
+		}
+		l.buffer = ebiten.NewImage(size.X, size.Y)
+	}
+	l.buffer.Clear()
 	for _, clip := range l.clips {
-		clip.Draw(screen)
+		clip.Draw(l.buffer)
 	}
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.ScaleAlpha(float32(l.alpha))
+	screen.DrawImage(l.buffer, op)
 }
 
 // Update updates the layer
 func (l *Layer) Update() (err error) {
+	if !l.visible {
+		return nil
+	}
 	for _, clip := range l.clips {
 		err = clip.Update()
 		if err != nil {
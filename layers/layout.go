@@ -0,0 +1,181 @@
+package layers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/mevdschee/ebiten-mines/clips"
+	"github.com/mevdschee/ebiten-mines/sprites"
+)
+
+// expression is a compiled integer expression of a clip in JSON
+type expression struct {
+	name    string
+	source  string
+	program *vm.Program
+}
+
+// compile compiles an expression, an empty expression evaluates to 0
+func compile(name, source string) (*expression, error) {
+	e := &expression{name: name, source: source}
+	if len(source) == 0 {
+		return e, nil
+	}
+	program, err := expr.Compile(source, expr.AsInt())
+	if err != nil {
+		return nil, fmt.Errorf("%s in '%s': %v", name, source, err)
+	}
+	e.program = program
+	return e, nil
+}
+
+func (e *expression) eval(parameters map[string]interface{}) (int, error) {
+	if e.program == nil {
+		return 0, nil
+	}
+	value, err := expr.Run(e.program, parameters)
+	if err != nil {
+		return 0, fmt.Errorf("%s in '%s': %v", e.name, e.source, err)
+	}
+	return value.(int), nil
+}
+
+// layout is a clip in JSON with its compiled expressions and the clips it
+// created, a clip that is added at runtime has a layout without a JSON
+type layout struct {
+	clipJSON *clips.ClipJSON
+	sprite   *sprites.Sprite
+	repeat   *expression
+	x, y     *expression
+	width    *expression
+	height   *expression
+	ticks    *expression
+	duration *expression
+	clips    []*clips.Clip
+}
+
+// newLayout compiles the expressions of a clip in JSON
+func newLayout(spriteMap sprites.SpriteMap, clipJSON clips.ClipJSON) (*layout, error) {
+	sprite, ok := spriteMap[clipJSON.Sprite]
+	if !ok && clipJSON.Sprite != "" {
+		return nil, fmt.Errorf("Could not find sprite '%s' for clip with name '%s'", clipJSON.Sprite, clipJSON.Name)
+	}
+	l := &layout{
+		clipJSON: &clipJSON,
+		sprite:   sprite,
+		clips:    []*clips.Clip{},
+	}
+	expressions := []struct {
+		e      **expression
+		name   string
+		source string
+	}{
+		{&l.repeat, "Repeat", clipJSON.Repeat},
+		{&l.x, "X", clipJSON.X},
+		{&l.y, "Y", clipJSON.Y},
+		{&l.width, "Width", clipJSON.Width},
+		{&l.height, "Height", clipJSON.Height},
+		{&l.ticks, "Ticks", clipJSON.Ticks},
+		{&l.duration, "Duration", clipJSON.Duration},
+	}
+	for _, e := range expressions {
+		compiled, err := compile(e.name, e.source)
+		if err != nil {
+			return nil, err
+		}
+		*e.e = compiled
+	}
+	switch clipJSON.Play {
+	case "", "once", "loop":
+	default:
+		return nil, fmt.Errorf("Play in '%s' is not 'once' or 'loop'", clipJSON.Play)
+	}
+	return l, nil
+}
+
+// update evaluates the layout of the clips, it moves and resizes the
+// clips that exist and creates or removes clips when the repeat changed
+func (l *layout) update(parameters map[string]interface{}) error {
+	if l.clipJSON == nil {
+		return nil
+	}
+	repeat, err := l.repeat.eval(parameters)
+	if err != nil {
+		return err
+	}
+	if l.clipJSON.Repeat == "" {
+		repeat = 1
+	}
+	if repeat < 0 {
+		repeat = 0
+	}
+	for i := 0; i < repeat; i++ {
+		parameters["i"] = i
+		x, err := l.x.eval(parameters)
+		if err != nil {
+			return err
+		}
+		y, err := l.y.eval(parameters)
+		if err != nil {
+			return err
+		}
+		width, err := l.width.eval(parameters)
+		if err != nil {
+			return err
+		}
+		height, err := l.height.eval(parameters)
+		if err != nil {
+			return err
+		}
+		if i < len(l.clips) {
+			l.clips[i].SetPosition(x, y)
+			l.clips[i].SetSize(width, height)
+			continue
+		}
+		var clip *clips.Clip
+		if l.sprite == nil {
+			clip = clips.NewText(l.clipJSON.Name, x, y, l.clipJSON.Text)
+		} else if width == 0 {
+			clip = clips.New(l.sprite, l.clipJSON.Name, x, y)
+		} else {
+			clip = clips.NewScaled(l.sprite, l.clipJSON.Name, x, y, width, height)
+		}
+		if err := l.animate(clip, parameters); err != nil {
+			return err
+		}
+		l.clips = append(l.clips, clip)
+	}
+	for _, clip := range l.clips[repeat:] {
+		clip.Dispose()
+	}
+	l.clips = l.clips[:repeat]
+	return nil
+}
+
+// animate sets up the animation of a new clip
+func (l *layout) animate(clip *clips.Clip, parameters map[string]interface{}) error {
+	ticks, err := l.ticks.eval(parameters)
+	if err != nil {
+		return err
+	}
+	if ticks > 0 {
+		clip.SetFrameTicks(ticks)
+	}
+	duration, err := l.duration.eval(parameters)
+	if err != nil {
+		return err
+	}
+	if duration > 0 {
+		clip.SetFrameDuration(time.Duration(duration) * time.Millisecond)
+	}
+	clip.SetPingPong(l.clipJSON.PingPong)
+	switch l.clipJSON.Play {
+	case "once":
+		clip.Play()
+	case "loop":
+		clip.Loop()
+	}
+	return nil
+}
@@ -3,16 +3,24 @@ package main
 import (
 	"bytes"
 	_ "embed"
+	"fmt"
 	"image"
 	"image/png"
 	"log"
-	"math/rand"
+	"os"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/mevdschee/ebiten-mines/board"
 	"github.com/mevdschee/ebiten-mines/clips"
+	"github.com/mevdschee/ebiten-mines/layers"
+	"github.com/mevdschee/ebiten-mines/mouse"
 	"github.com/mevdschee/ebiten-mines/movies"
+	"github.com/mevdschee/ebiten-mines/replay"
+	"github.com/mevdschee/ebiten-mines/scenes"
 	"github.com/mevdschee/ebiten-mines/sprites"
+	"github.com/mevdschee/ebiten-mines/stats"
 	"github.com/mevdschee/ebiten-mines/touch"
 )
 
@@ -28,7 +36,9 @@ const spriteMapMeta = `
 	{"name":"digits","x":0,"y":33,"width":11,"height":21,"count":11,"gap":1},
 	{"name":"buttons","x":0,"y":55,"width":26,"height":26,"count":5,"gap":1},
 	{"name":"controls","x":0,"y":82,"widths":[12,1,12],"heights":[11,1,11],"gap":1},
-	{"name":"field","x":0,"y":96,"widths":[12,1,12],"heights":[11,1,11],"gap":1}]`
+	{"name":"field","x":0,"y":96,"widths":[12,1,12],"heights":[11,1,11],"gap":1},
+	{"name":"cursor","x":74,"y":84,"width":16,"height":16,"count":2,"gap":1},
+	{"name":"heat","x":0,"y":123,"width":16,"height":16,"count":12,"grid":6,"gap":1}]`
 
 const movieScenes = `
 	[{"name":"game","layers":[{"name":"bg","clips":[
@@ -40,42 +50,117 @@ const movieScenes = `
 		{"sprite":"digits","name":"bombs","repeat":"3","x":"18+i*13","y":"17"},
 		{"sprite":"digits","name":"time","repeat":"3","x":"w*16-31+i*13","y":"17"},
 		{"sprite":"buttons","name":"button","x":"(w*16)/2-1","y":"15"},
-		{"sprite":"icons","name":"icons","repeat":"w*h","x":"12+(i%w)*16","y":"55+floor(i/w)*16"}
+		{"sprite":"icons","name":"icons","repeat":"w*h","x":"12+(i%w)*16","y":"55+floor(i/w)*16"},
+		{"sprite":"cursor","name":"cursor","x":"12","y":"55"}
+	]},{"name":"heat","visible":false,"clips":[
+		{"sprite":"heat","name":"heat","repeat":"w*h","x":"12+(i%w)*16","y":"55+floor(i/w)*16"},
+		{"name":"message","x":"14","y":"57"}
+	]},{"name":"paused","visible":false,"modal":true,"clips":[
+		{"sprite":"field","x":"0","y":"44","width":"w*16+24","height":"h*16+22"},
+		{"name":"resume","text":"      Paused\nclick or press P","x":"(w*16+24)/2-49","y":"h*8+39"}
+	]},{"name":"results","visible":false,"clips":[
+		{"sprite":"controls","name":"panel","x":"0","y":"44","width":"w*16+24","height":"92"},
+		{"name":"time","x":"w*16-63","y":"48"},
+		{"name":"info","x":"8","y":"68"}
+	]},{"name":"replay","visible":false,"modal":true,"clips":[
+		{"sprite":"controls","x":"0","y":"h*16+66","width":"w*16+24","height":"56"},
+		{"name":"seek","repeat":"w","x":"12+i*16","y":"h*16+70"},
+		{"name":"prev","text":"[<]","x":"8","y":"h*16+86"},
+		{"name":"play","x":"32","y":"h*16+86"},
+		{"name":"next","text":"[>]","x":"62","y":"h*16+86"},
+		{"name":"slower","text":"[-]","x":"86","y":"h*16+86"},
+		{"name":"speed","x":"106","y":"h*16+86"},
+		{"name":"faster","text":"[+]","x":"134","y":"h*16+86"},
+		{"name":"position","x":"8","y":"h*16+102"}
+	]}]},{"name":"menu","layers":[{"name":"bg","clips":[
+		{"sprite":"controls","x":"0","y":"0","width":"w*16+24","height":"h*16+66"}
+	]},{"name":"fg","clips":[
+		{"name":"beginner","x":"8","y":"8"},
+		{"name":"intermediate","x":"8","y":"24"},
+		{"name":"expert","x":"8","y":"40"},
+		{"name":"custom","x":"8","y":"56"},
+		{"text":"Width","x":"24","y":"76"},
+		{"text":"Height","x":"24","y":"92"},
+		{"text":"Mines","x":"24","y":"108"},
+		{"name":"less","text":"[-]","repeat":"3","x":"78","y":"76+i*16"},
+		{"name":"value","repeat":"3","x":"102","y":"76+i*16"},
+		{"name":"more","text":"[+]","repeat":"3","x":"126","y":"76+i*16"},
+		{"text":"Start","x":"24","y":"128"},
+		{"name":"placement","x":"78","y":"128"},
+		{"text":"Marks","x":"24","y":"144"},
+		{"name":"questions","x":"78","y":"144"},
+		{"text":"Mode","x":"24","y":"160"},
+		{"name":"practice","x":"78","y":"160"},
+		{"name":"error","x":"8","y":"176"},
+		{"name":"back","text":"Back","x":"8","y":"h*16+48"},
+		{"name":"stats","text":"Stats","x":"60","y":"h*16+48"}
+	]}]},{"name":"stats","layers":[{"name":"bg","clips":[
+		{"sprite":"controls","x":"0","y":"0","width":"w*16+24","height":"h*16+66"}
+	]},{"name":"fg","clips":[
+		{"name":"prev","text":"[<]","x":"8","y":"6"},
+		{"name":"title","x":"32","y":"6"},
+		{"name":"next","text":"[>]","x":"w*16-3","y":"6"},
+		{"name":"summary","x":"8","y":"26"},
+		{"name":"times","x":"8","y":"62"},
+		{"name":"back","text":"Back","x":"8","y":"h*16+44"},
+		{"name":"reset","x":"w*16-39","y":"h*16+44"}
 	]}]}]`
 
 type config struct {
-	scale   int
-	width   int
-	height  int
-	bombs   int
-	holding int
+	scale     int
+	width     int
+	height    int
+	bombs     int
+	seed      int64
+	placement board.Placement
+	questions bool
+	tps       int
+	holding   int
+	name      string
+	resume    bool
+	replay    string
+	autoFlag  bool
+	autoOpen  bool
+	practice  bool
 }
 
 type game struct {
-	c      config
-	movie  *movies.Movie
-	button int
-	bombs  int
-	closed int
-	state  int
-	time   int64
-	tiles  [][]tile
-}
+	c       config
+	movie   *movies.Movie
+	button  int
+	paused  bool
+	results bool
+	seed    int64
+	board   *board.Board
+	replay  *replay.Replay
+	pressed [][]bool
+	custom  config
 
-type tile struct {
-	open    bool
-	marked  bool
-	bomb    bool
-	pressed bool
-	number  int
-}
+	pressing bool
+	pressX   int
+	pressY   int
+	cursor   bool
+	cursorX  int
+	cursorY  int
 
-const (
-	stateWaiting = iota
-	statePlaying
-	stateWon
-	stateLost
-)
+	heatmap       bool
+	probabilities [][]float64
+	hinting       bool
+	hintX         int
+	hintY         int
+	message       string
+
+	menuError   string
+	pressedClip *clips.Clip
+	stats       *stats.Stats
+	statsKey    string
+	statsReset  bool
+
+	player      *replay.Player
+	playing     bool
+	speed       int
+	replayTicks float64
+}
 
 const (
 	buttonPlaying = iota
@@ -107,7 +192,12 @@ const (
 
 var clipCache map[string][]*clips.Clip
 
+var exitHandler func()
+
 func (g *game) getSize() (int, int) {
+	if g.player != nil {
+		return g.c.width*16 + 12*2, g.c.height*16 + 11*3 + 33 + replayHeight
+	}
 	return g.c.width*16 + 12*2, g.c.height*16 + 11*3 + 33
 }
 
@@ -120,36 +210,73 @@ func (g *game) init() {
 	if err != nil {
 		log.Fatalln(err)
 	}
-	parameters := map[string]interface{}{
-		"w": g.c.width,
-		"h": g.c.height,
-	}
-	movie, err := movies.FromJSON(spriteMap, movieScenes, parameters)
+	movie, err := movies.FromJSON(spriteMap, movieScenes, g.parameters())
 	if err != nil {
 		log.Fatalln(err)
 	}
+	movie.SetTransition(movies.Fade, ebiten.TPS()/5)
 	g.movie = movie
 	clipCache = map[string][]*clips.Clip{}
 }
 
-func (g *game) getClips(clip string) []*clips.Clip {
+// parameters gets the values of the variables in the layout expressions
+func (g *game) parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"w": g.c.width,
+		"h": g.c.height,
+	}
+}
+
+// layout lays out the movie again for the size of the board, the tiles
+// that were added get handlers
+func (g *game) layout() {
+	tiles := len(g.getClips("game", "icons"))
+	if err := g.movie.SetParameters(g.parameters()); err != nil {
+		log.Fatal(err)
+	}
+	clipCache = map[string][]*clips.Clip{}
+	g.setTileHandlers(tiles)
+}
+
+func (g *game) getScene(name string) *scenes.Scene {
+	scene, err := g.movie.GetScene(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return scene
+}
+
+func (g *game) getLayer(scene, layer string) *layers.Layer {
+	l, err := g.movie.GetLayer(scene, layer)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return l
+}
+
+func (g *game) getClips(scene, clip string) []*clips.Clip {
+	return g.getLayerClips(scene, "fg", clip)
+}
+
+func (g *game) getLayerClips(scene, layer, clip string) []*clips.Clip {
 	if clipCache == nil {
 		clipCache = map[string][]*clips.Clip{}
 	}
-	cache, ok := clipCache[clip]
+	key := scene + "." + layer + "." + clip
+	cache, ok := clipCache[key]
 	if ok {
 		return cache
 	}
-	clips, err := g.movie.GetClips("game", "fg", clip)
+	clips, err := g.movie.GetClips(scene, layer, clip)
 	if err != nil {
 		log.Fatal(err)
 	}
-	clipCache[clip] = clips
+	clipCache[key] = clips
 	return clips
 }
 
 func (g *game) setHandlers() {
-	button := g.getClips("button")[0]
+	button := g.getClips("game", "button")[0]
 	button.OnPress(func() {
 		g.button = buttonPressed
 	})
@@ -163,135 +290,242 @@ func (g *game) setHandlers() {
 			g.restart()
 		}
 	})
-	icons := g.getClips("icons")
-	for y := 0; y < g.c.height; y++ {
-		for x := 0; x < g.c.width; x++ {
-			px, py := x, y
-			icons[y*g.c.width+x].OnPress(func() {
-				if g.state == stateWon || g.state == stateLost {
-					return
-				}
-				if g.tiles[py][px].marked {
-					return
-				}
-				g.button = buttonEvaluate
-				g.tiles[py][px].pressed = true
-				if g.tiles[py][px].open {
-					g.forEachNeighbour(px, py, func(x, y int) {
-						if !g.tiles[y][x].marked {
-							g.tiles[y][x].pressed = true
-						}
-					})
-				}
-			})
-			icons[y*g.c.width+x].OnLongPress(func() {
-				if g.state == stateWon || g.state == stateLost {
-					return
-				}
-				g.onPressTile(px, py, true)
-				g.tiles[py][px].pressed = false
-			})
-			icons[y*g.c.width+x].OnRelease(func() {
-				if g.state == stateWon || g.state == stateLost {
-					return
-				}
-				g.button = buttonPlaying
-				if g.tiles[py][px].open {
-					g.onPressTile(px, py, true)
-				} else {
-					if g.tiles[py][px].pressed {
-						g.onPressTile(px, py, false)
-					}
-				}
-				g.tiles[py][px].pressed = false
-			})
-			icons[y*g.c.width+x].OnReleaseOutside(func() {
-				if g.state == stateWon || g.state == stateLost {
-					return
-				}
-				g.button = buttonPlaying
-				g.tiles[py][px].pressed = false
-			})
+	button.OnLongPress(func() {
+		g.button = buttonPlaying
+		g.openMenu()
+	})
+	g.setTileHandlers(0)
+	g.onClick(g.getLayerClips("game", "heat", "message")[0], func() {
+		if g.message == undoMessage {
+			g.undo()
 		}
-	}
+	})
+	g.onClick(g.getLayerClips("game", "paused", "resume")[0], func() {
+		g.paused = false
+	})
+	g.onClick(g.getLayerClips("game", "results", "panel")[0], func() {
+		g.results = false
+	})
+	g.setMenuHandlers()
+	g.setStatsHandlers()
+	g.setReplayHandlers()
 }
 
-func (g *game) forEachNeighbour(x, y int, do func(x, y int)) {
-	for i := 0; i < 9; i++ {
-		dy, dx := i/3-1, i%3-1
-		if dy == 0 && dx == 0 {
-			continue
-		}
-		if y+dy < 0 || x+dx < 0 {
-			continue
-		}
-		if y+dy >= g.c.height || x+dx >= g.c.width {
-			continue
+// setTileHandlers sets the handlers of the tiles from an index on, the
+// handlers look up the position of the tile when they are called so that
+// they stay right when the board is resized
+func (g *game) setTileHandlers(from int) {
+	icons := g.getClips("game", "icons")
+	for i := from; i < len(icons); i++ {
+		i := i
+		tile := func(handler func(x, y int)) func() {
+			return func() {
+				handler(i%g.c.width, i/g.c.width)
+			}
 		}
-		do(x+dx, y+dy)
+		icons[i].OnPress(tile(g.pressTile))
+		icons[i].OnLongPress(tile(g.longPressTile))
+		icons[i].OnRelease(tile(g.releaseTile))
+		icons[i].OnReleaseOutside(tile(g.cancelPress))
+		icons[i].OnChordPress(tile(g.chordPressTile))
+		icons[i].OnChordRelease(tile(g.chordReleaseTile))
 	}
 }
 
-func (g *game) onPressTile(x, y int, long bool) {
-	if g.state == stateWaiting {
-		g.state = statePlaying
-		g.time = time.Now().UnixNano()
-		g.placeBombs(x, y, g.bombs)
+func (g *game) pressTile(x, y int) {
+	if g.isOver() {
+		return
 	}
-	if !long && g.tiles[y][x].marked {
+	cell := g.board.Cell(x, y)
+	if cell.Marked {
 		return
 	}
-	if g.tiles[y][x].open {
-		if long {
-			var marked = 0
-			g.forEachNeighbour(x, y, func(x, y int) {
-				if g.tiles[y][x].marked {
-					marked++
-				}
-			})
-			if g.tiles[y][x].number == marked {
-				g.forEachNeighbour(x, y, func(x, y int) {
-					if !g.tiles[y][x].marked {
-						g.onPressTile(x, y, false)
-					}
-				})
+	g.record(replay.Press, x, y)
+	g.button = buttonEvaluate
+	g.pressing = true
+	g.pressX, g.pressY = x, y
+	g.pressed[y][x] = true
+	if cell.Open {
+		g.board.ForEachNeighbour(x, y, func(x, y int) {
+			if !g.board.Cell(x, y).Marked {
+				g.pressed[y][x] = true
 			}
+		})
+	}
+}
+
+func (g *game) longPressTile(x, y int) {
+	if g.isOver() {
+		return
+	}
+	g.onPressTile(x, y, true)
+	if g.pressing && g.pressX == x && g.pressY == y {
+		g.clearPressed()
+	}
+}
+
+func (g *game) releaseTile(x, y int) {
+	if g.isOver() {
+		return
+	}
+	pressed := g.pressing && g.pressX == x && g.pressY == y
+	if g.pressing {
+		g.record(replay.Release, x, y)
+	}
+	g.button = buttonPlaying
+	g.clearPressed()
+	if pressed {
+		g.onPressTile(x, y, g.board.Cell(x, y).Open)
+	}
+}
+
+func (g *game) chordPressTile(x, y int) {
+	if g.isOver() {
+		return
+	}
+	g.record(replay.ChordPress, x, y)
+	g.clearPressed()
+	g.button = buttonEvaluate
+	g.pressing = true
+	g.pressX, g.pressY = x, y
+	if cell := g.board.Cell(x, y); !cell.Open && !cell.Marked {
+		g.pressed[y][x] = true
+	}
+	g.board.ForEachNeighbour(x, y, func(x, y int) {
+		if cell := g.board.Cell(x, y); !cell.Open && !cell.Marked {
+			g.pressed[y][x] = true
+		}
+	})
+}
+
+func (g *game) chordReleaseTile(x, y int) {
+	if g.isOver() {
+		return
+	}
+	pressed := g.pressing && g.pressX == x && g.pressY == y
+	if g.pressing {
+		g.record(replay.Release, x, y)
+	}
+	g.button = buttonPlaying
+	g.clearPressed()
+	if pressed && g.board.Cell(x, y).Open {
+		g.onPressTile(x, y, true)
+	}
+}
+
+func (g *game) cancelPress(x, y int) {
+	if g.isOver() {
+		return
+	}
+	if g.pressing && g.pressX == x && g.pressY == y {
+		g.record(replay.Release, x, y)
+		g.button = buttonPlaying
+		g.clearPressed()
+	}
+}
+
+func (g *game) isOver() bool {
+	state := g.board.State()
+	return state == board.Won || state == board.Lost
+}
+
+func (g *game) clearPressed() {
+	g.pressing = false
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			g.pressed[y][x] = false
+		}
+	}
+}
+
+func (g *game) onPressTile(x, y int, long bool) {
+	g.hinting = false
+	g.message = ""
+	waiting := g.board.State() == board.Waiting
+	if g.board.Cell(x, y).Open {
+		if long {
+			g.record(replay.Chord, x, y)
+			g.board.Chord(x, y)
 		}
 	} else {
 		if long {
-			if g.tiles[y][x].marked {
-				g.tiles[y][x].marked = false
-				g.bombs++
-			} else {
-				g.tiles[y][x].marked = true
-				g.bombs--
-			}
+			g.record(replay.Flag, x, y)
+			g.board.ToggleFlag(x, y)
 		} else {
-			g.tiles[y][x].open = true
-			g.closed--
-			if g.tiles[y][x].bomb {
-				g.state = stateLost
-				g.button = buttonLost
-				return
-			}
-			if g.tiles[y][x].number == 0 {
-				g.forEachNeighbour(x, y, func(x, y int) {
-					g.onPressTile(x, y, false)
-				})
-			}
+			g.record(replay.Open, x, y)
+			g.board.Open(x, y)
+		}
+	}
+	if waiting && g.board.Guessing() {
+		log.Printf("board with seed %d could not be made solvable without guessing\n", g.seed)
+		g.message = "May need a guess"
+	}
+	g.assist()
+	g.endMove()
+}
+
+// endMove shows the result of a move on the board
+func (g *game) endMove() {
+	switch g.board.State() {
+	case board.Won:
+		g.button = buttonWon
+	case board.Lost:
+		g.button = buttonLost
+	}
+	if g.board.State() == board.Lost && g.board.CanUndo() {
+		g.message = undoMessage
+		return
+	}
+	if g.isOver() {
+		g.showSeed()
+		g.recordStats()
+		g.saveReplay()
+		g.results = true
+		g.heatmap = false
+		for _, name := range []string{"panel", "time", "info"} {
+			clip := g.getLayerClips("game", "results", name)[0]
+			clip.SetAlpha(0)
+			clip.TweenTo(clips.Props{clips.Alpha: 1}, 200*time.Millisecond, clips.EaseOutQuad)
 		}
 	}
+	g.updateHeatmap()
+}
+
+func (g *game) showSeed() {
+	log.Printf("game %s, replay it using seed %d\n", g.board.State(), g.seed)
+	ebiten.SetWindowTitle(fmt.Sprintf("Ebiten Mines (seed %d)", g.seed))
+}
+
+// setOverlays shows the layers that are drawn over the board
+func (g *game) setOverlays() {
+	replaying := g.player != nil
+	g.getLayer("game", "heat").SetVisible(g.heatmap || g.hinting || g.message != "")
+	g.getLayer("game", "paused").SetVisible(!replaying && g.paused)
+	g.getLayer("game", "results").SetVisible(!replaying && !g.paused && g.results)
+	g.getLayer("game", "replay").SetVisible(replaying)
+}
+
+func (g *game) setResults() {
+	if !g.getLayer("game", "results").IsVisible() {
+		return
+	}
+	ms := g.board.Elapsed().Milliseconds()
+	g.getLayerClips("game", "results", "time")[0].SetText(fmt.Sprintf("Time %d.%03ds", ms/1000, ms%1000))
+	m := g.board.Metrics()
+	g.getLayerClips("game", "results", "info")[0].SetText(fmt.Sprintf("Seed %d\n%dx%d, %d mines\n3BV %d  3BV/s %.2f\nClicks %d+%d+%d  Eff %d%%",
+		g.seed, g.board.Width(), g.board.Height(), g.board.Mines(),
+		m.ThreeBV, m.ThreeBVPerSecond(), m.LeftClicks, m.RightClicks, m.ChordClicks, m.Efficiency()))
 }
 
 func (g *game) setButton() {
-	button := g.getClips("button")[0]
+	button := g.getClips("game", "button")[0]
 	button.GotoFrame(g.button)
 }
 
 func (g *game) setNumbers() {
-	bombsDigits := g.getClips("bombs")
-	bombs := g.bombs
-	if g.state == stateWon {
+	bombsDigits := g.getClips("game", "bombs")
+	bombs := g.board.Remaining()
+	if g.board.State() == board.Won {
 		bombs = 0
 	}
 	if bombs < -99 {
@@ -310,41 +544,46 @@ func (g *game) setNumbers() {
 		}
 		bombs /= 10
 	}
-	if g.state == statePlaying || g.state == stateWaiting {
-		time := int((time.Now().UnixNano() - g.time) / 1000000000)
-		if time > 999 {
-			time = 999
+	if !g.isOver() {
+		seconds := int(g.board.Elapsed() / time.Second)
+		if seconds > 999 {
+			seconds = 999
 		}
-		timeDigits := g.getClips("time")
+		timeDigits := g.getClips("game", "time")
 		for i := 0; i < 3; i++ {
-			timeDigits[2-i].GotoFrame(time % 10)
-			time /= 10
+			timeDigits[2-i].GotoFrame(seconds % 10)
+			seconds /= 10
 		}
 	}
 }
 
 func (g *game) setTiles() {
-	icons := g.getClips("icons")
-	if g.state == stateWon || g.state == stateLost {
+	icons := g.getClips("game", "icons")
+	state := g.board.State()
+	if g.isOver() {
 		for y := 0; y < g.c.height; y++ {
 			for x := 0; x < g.c.width; x++ {
+				cell := g.board.Cell(x, y)
 				icon := iconClosed
-				if g.tiles[y][x].open {
-					if g.tiles[y][x].bomb {
+				if cell.Open {
+					if cell.Mine {
 						icon = iconAnswerIsBomb
 					} else {
-						icon = g.tiles[y][x].number
+						icon = cell.Number
 					}
 				} else {
-					if g.tiles[y][x].marked {
-						if g.tiles[y][x].bomb {
+					if cell.Marked {
+						if cell.Mine {
 							icon = iconMarked
 						} else {
 							icon = iconAnswerNoBomb
 						}
 					} else {
-						if g.tiles[y][x].bomb {
-							if g.state == stateWon {
+						if cell.Question {
+							icon = iconQuestionMark
+						}
+						if cell.Mine {
+							if state == board.Won {
 								icon = iconMarked
 							} else {
 								icon = iconBomb
@@ -358,19 +597,22 @@ func (g *game) setTiles() {
 	} else {
 		for y := 0; y < g.c.height; y++ {
 			for x := 0; x < g.c.width; x++ {
+				cell := g.board.Cell(x, y)
 				icon := iconClosed
-				if g.tiles[y][x].open {
-					icon = g.tiles[y][x].number
+				if cell.Open {
+					icon = cell.Number
 				} else {
-					if g.tiles[y][x].marked {
+					if cell.Marked {
 						icon = iconMarked
+					} else if cell.Question {
+						icon = iconQuestionMark
+						if g.pressed[y][x] {
+							icon = iconQuestionPressed
+						}
 					} else {
-						if g.tiles[y][x].pressed {
+						if g.pressed[y][x] {
 							icon = iconEmpty
 						}
-						if g.state == stateWon {
-							icon = iconMarked
-						}
 					}
 				}
 				icons[y*g.c.width+x].GotoFrame(icon)
@@ -384,19 +626,45 @@ func (g *game) Update() error {
 		g.init()
 		g.setHandlers()
 	}
-	if g.state == stateWaiting {
-		g.time = time.Now().UnixNano()
+	if ebiten.IsWindowBeingClosed() {
+		if exitHandler != nil {
+			exitHandler()
+		}
+		return ebiten.Termination
+	}
+	if g.player != nil {
+		g.updateReplay()
+	} else {
+		if !ebiten.IsFocused() && g.board.State() == board.Playing {
+			g.paused = true
+		}
+		// the clock stops while the menu or the stats are shown
+		if !g.paused && g.movie.GetSceneName() == "game" {
+			g.board.Tick(time.Second / time.Duration(ebiten.TPS()))
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			if g.movie.GetSceneName() == "menu" {
+				g.closeMenu()
+			} else {
+				g.openMenu()
+			}
+		}
+		if g.movie.GetSceneName() == "game" {
+			g.handleKeys()
+		}
 	}
 	g.setButton()
 	g.setNumbers()
 	g.setTiles()
-	if g.state == statePlaying {
-		if g.closed == g.c.bombs {
-			g.state = stateWon
-			g.button = buttonWon
-		}
-	}
+	g.setCursor()
+	g.setHeat()
+	g.setOverlays()
+	g.setResults()
+	g.setReplay()
+	g.setMenu()
+	g.setStats()
 	touch.UpdateTouchIDs()
+	mouse.UpdateButtons()
 	return g.movie.Update()
 }
 
@@ -406,53 +674,84 @@ func (g *game) Draw(screen *ebiten.Image) {
 
 func newGame(c config) *game {
 	g := &game{c: c}
+	s, err := stats.Load()
+	if err != nil {
+		log.Printf("could not load stats: %v\n", err)
+	}
+	g.stats = s
 	return g
 }
 
+// restart starts a new game, a game that is left before it ended or after
+// hitting a mine that was not undone counts as lost and its replay is saved
+// first
 func (g *game) restart() {
-	g.button = buttonPlaying
-	g.bombs = g.c.bombs
-	g.closed = g.c.width * g.c.height
-	g.state = stateWaiting
-	g.time = time.Now().UnixNano()
-	g.tiles = make([][]tile, g.c.height)
-	for y := 0; y < g.c.height; y++ {
-		g.tiles[y] = make([]tile, g.c.width)
-		for x := 0; x < g.c.width; x++ {
-			g.tiles[y][x] = tile{}
-		}
+	if g.board != nil && g.replayPending() {
+		g.recordStats()
+		g.saveReplay()
 	}
+	seed := g.c.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	g.setBoard(board.New(board.Config{
+		Width:     g.c.width,
+		Height:    g.c.height,
+		Mines:     g.c.bombs,
+		Seed:      seed,
+		Placement: g.c.placement,
+		Questions: g.c.questions,
+		Practice:  g.c.practice,
+	}))
 }
 
-func (g *game) placeBombs(x, y, bombs int) {
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	b := bombs
-	g.tiles[y][x].bomb = true
-	for b > 0 {
-		x, y := rng.Intn(g.c.width), rng.Intn(g.c.height)
-		if !g.tiles[y][x].bomb {
-			g.tiles[y][x].bomb = true
-			b--
-			g.forEachNeighbour(x, y, func(x, y int) {
-				g.tiles[y][x].number++
-			})
-		}
+func (g *game) setBoard(b *board.Board) {
+	g.board = b
+	g.replay = g.newReplay()
+	g.seed = b.Seed()
+	g.paused = false
+	g.results = false
+	g.button = buttonPlaying
+	switch b.State() {
+	case board.Won:
+		g.button = buttonWon
+	case board.Lost:
+		g.button = buttonLost
+	}
+	g.cursorX = clamp(g.cursorX, 0, g.c.width-1)
+	g.cursorY = clamp(g.cursorY, 0, g.c.height-1)
+	ebiten.SetWindowTitle("Ebiten Mines")
+	g.pressing = false
+	g.heatmap = false
+	g.hinting = false
+	g.message = ""
+	g.pressed = make([][]bool, g.c.height)
+	for y := 0; y < g.c.height; y++ {
+		g.pressed[y] = make([]bool, g.c.width)
 	}
-	g.tiles[y][x].bomb = false
 }
 
 func main() {
-	g := newGame(config{
-		scale:   1,
-		width:   9,
-		height:  9,
-		bombs:   10,
-		holding: 15,
-	})
-	g.restart()
+	c, err := parseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ebiten-mines: %v\n", err)
+		os.Exit(2)
+	}
+	g := newGame(c)
+	if g.c.replay != "" {
+		if err := g.loadReplay(g.c.replay); err != nil {
+			fmt.Fprintf(os.Stderr, "ebiten-mines: -replay: %v\n", err)
+			os.Exit(2)
+		}
+	} else {
+		if !g.c.resume || !g.loadGame() {
+			g.restart()
+		}
+		onExit(g.saveGame)
+	}
 	width, height := g.getSize()
-	ebiten.SetWindowTitle("Ebiten Mines")
-	ebiten.SetTPS(30)
+	clips.SetHoldDuration(time.Duration(g.c.holding) * time.Millisecond)
+	ebiten.SetTPS(g.c.tps)
 	ebiten.SetWindowSize(g.c.scale*width, g.c.scale*height)
 	icon, err := png.Decode(bytes.NewReader(minesIconImage))
 	if err == nil {
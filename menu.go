@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/clips"
+)
+
+type difficulty struct {
+	name   string
+	title  string
+	width  int
+	height int
+	bombs  int
+}
+
+var difficulties = []difficulty{
+	{"beginner", "Beginner", 9, 9, 10},
+	{"intermediate", "Intermediate", 16, 16, 40},
+	{"expert", "Expert", 30, 16, 99},
+}
+
+const (
+	minWidth  = board.MinWidth
+	maxWidth  = board.MaxWidth
+	minHeight = board.MinHeight
+	maxHeight = board.MaxHeight
+	maxBombs  = 999
+)
+
+func (c config) difficulty() string {
+	for _, d := range difficulties {
+		if c.width == d.width && c.height == d.height && c.bombs == d.bombs {
+			return d.name
+		}
+	}
+	return "custom"
+}
+
+func (c config) withDifficulty(d difficulty) config {
+	c.width = d.width
+	c.height = d.height
+	c.bombs = d.bombs
+	return c
+}
+
+func (c config) validate() error {
+	if c.width < minWidth || c.width > maxWidth {
+		return fmt.Errorf("Width must be %d-%d", minWidth, maxWidth)
+	}
+	if c.height < minHeight || c.height > maxHeight {
+		return fmt.Errorf("Height must be %d-%d", minHeight, maxHeight)
+	}
+	if c.bombs > maxBombs {
+		return fmt.Errorf("Mines must be at most %d", maxBombs)
+	}
+	return board.Config{Width: c.width, Height: c.height, Mines: c.bombs, Placement: c.placement}.Validate()
+}
+
+func (g *game) configure(c config) {
+	g.c = c
+	g.restart()
+	g.layout()
+	width, height := g.getSize()
+	ebiten.SetWindowSize(g.c.scale*width, g.c.scale*height)
+	g.closeMenu()
+}
+
+func (g *game) openMenu() {
+	if err := g.movie.GotoScene("menu"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *game) closeMenu() {
+	if err := g.movie.GotoScene("game"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *game) onClick(clip *clips.Clip, handler func()) {
+	clip.OnPress(func() {
+		g.pressedClip = clip
+	})
+	clip.OnRelease(func() {
+		if g.pressedClip == clip {
+			handler()
+		}
+		g.pressedClip = nil
+	})
+	clip.OnReleaseOutside(func() {
+		if g.pressedClip == clip {
+			g.pressedClip = nil
+		}
+	})
+}
+
+func (g *game) setMenuHandlers() {
+	g.getScene("menu").OnEnter(func() {
+		g.custom = g.c
+		g.menuError = ""
+		g.pressedClip = nil
+	})
+	for _, d := range difficulties {
+		d := d
+		g.onClick(g.getClips("menu", d.name)[0], func() {
+			g.configure(g.custom.withDifficulty(d))
+		})
+	}
+	g.onClick(g.getClips("menu", "custom")[0], func() {
+		if err := g.custom.validate(); err != nil {
+			g.menuError = err.Error()
+			return
+		}
+		g.configure(g.custom)
+	})
+	g.onClick(g.getClips("menu", "placement")[0], func() {
+		g.custom.placement = (g.custom.placement + 1) % (board.NoGuess + 1)
+		g.menuError = ""
+	})
+	g.onClick(g.getClips("menu", "questions")[0], func() {
+		g.custom.questions = !g.custom.questions
+	})
+	g.onClick(g.getClips("menu", "practice")[0], func() {
+		g.custom.practice = !g.custom.practice
+	})
+	g.onClick(g.getClips("menu", "back")[0], func() {
+		g.closeMenu()
+	})
+	g.onClick(g.getClips("menu", "stats")[0], func() {
+		g.openStats()
+	})
+	values := []*int{&g.custom.width, &g.custom.height, &g.custom.bombs}
+	less := g.getClips("menu", "less")
+	more := g.getClips("menu", "more")
+	for i, value := range values {
+		value := value
+		g.onClick(less[i], func() {
+			g.stepCustom(value, -1)
+		})
+		less[i].OnLongPress(func() {
+			g.pressedClip = nil
+			g.stepCustom(value, -10)
+		})
+		g.onClick(more[i], func() {
+			g.stepCustom(value, 1)
+		})
+		more[i].OnLongPress(func() {
+			g.pressedClip = nil
+			g.stepCustom(value, 10)
+		})
+	}
+}
+
+func (g *game) stepCustom(value *int, step int) {
+	*value += step
+	g.custom.width = clamp(g.custom.width, minWidth, maxWidth)
+	g.custom.height = clamp(g.custom.height, minHeight, maxHeight)
+	g.custom.bombs = clamp(g.custom.bombs, 1, maxBombs)
+	g.menuError = ""
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func (g *game) setMenu() {
+	current := g.c.difficulty()
+	for _, d := range difficulties {
+		g.getClips("menu", d.name)[0].SetText(menuItem(d.title, fmt.Sprintf("%dx%d/%d", d.width, d.height, d.bombs), d.name == current))
+	}
+	g.getClips("menu", "custom")[0].SetText(menuItem("Custom", "", current == "custom"))
+	values := g.getClips("menu", "value")
+	for i, value := range []int{g.custom.width, g.custom.height, g.custom.bombs} {
+		values[i].SetText(fmt.Sprintf("%3d", value))
+	}
+	g.getClips("menu", "placement")[0].SetText(g.custom.placement.String())
+	marks := "flag"
+	if g.custom.questions {
+		marks = "flag, ?"
+	}
+	g.getClips("menu", "questions")[0].SetText(marks)
+	mode := "normal"
+	if g.custom.practice {
+		mode = "practice"
+	}
+	g.getClips("menu", "practice")[0].SetText(mode)
+	g.getClips("menu", "error")[0].SetText(g.menuError)
+}
+
+func menuItem(title, size string, selected bool) string {
+	marker := " "
+	if selected {
+		marker = "*"
+	}
+	return fmt.Sprintf("%s %-13s%s", marker, title, size)
+}
@@ -0,0 +1,98 @@
+package mouse
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+var (
+	chording          bool
+	chordMiddle       bool
+	ignoreLeft        bool
+	justChordPressed  bool
+	justChordReleased bool
+	justLeftPressed   bool
+	justLeftReleased  bool
+	justRightPressed  bool
+)
+
+// UpdateButtons combines the mouse buttons into presses, releases and
+// chords, a chord is pressing left and right together or the middle button
+func UpdateButtons() {
+	left := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	right := ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight)
+	leftPressed := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	leftReleased := inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft)
+	rightPressed := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
+	middlePressed := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle)
+	middleReleased := inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonMiddle)
+
+	justChordPressed = false
+	justChordReleased = false
+	justLeftPressed = false
+	justLeftReleased = false
+	justRightPressed = false
+
+	if chording {
+		if (chordMiddle && middleReleased) || (!chordMiddle && (!left || !right)) {
+			chording = false
+			justChordReleased = true
+			ignoreLeft = left
+		}
+		return
+	}
+	if middlePressed || (left && right && (leftPressed || rightPressed)) {
+		chording = true
+		chordMiddle = middlePressed
+		justChordPressed = true
+		return
+	}
+	if leftReleased {
+		justLeftReleased = !ignoreLeft
+		ignoreLeft = false
+	}
+	justLeftPressed = leftPressed
+	justRightPressed = rightPressed
+}
+
+// IsChording returns whether or not a chord is being held
+func IsChording() bool {
+	return chording
+}
+
+// IsChordJustPressed returns whether or not a chord started in this tick
+func IsChordJustPressed() bool {
+	return justChordPressed
+}
+
+// IsChordJustReleased returns whether or not a chord ended in this tick
+func IsChordJustReleased() bool {
+	return justChordReleased
+}
+
+// IsLeftJustPressed returns whether or not the left button was pressed
+// in this tick without starting a chord
+func IsLeftJustPressed() bool {
+	return justLeftPressed
+}
+
+// IsLeftJustReleased returns whether or not the left button was released
+// in this tick without being part of a chord
+func IsLeftJustReleased() bool {
+	return justLeftReleased
+}
+
+// IsRightJustPressed returns whether or not the right button was pressed
+// in this tick without starting a chord
+func IsRightJustPressed() bool {
+	return justRightPressed
+}
+
+// LeftPressDuration gets the ticks the left button is held, it is zero
+// while the left button is part of a chord
+func LeftPressDuration() int {
+	if chording || ignoreLeft {
+		return 0
+	}
+	return inpututil.MouseButtonPressDuration(ebiten.MouseButtonLeft)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/mevdschee/ebiten-mines/clips"
+	"github.com/mevdschee/ebiten-mines/layers"
 	"github.com/mevdschee/ebiten-mines/scenes"
 	"github.com/mevdschee/ebiten-mines/sprites"
 )
@@ -14,6 +15,13 @@ import (
 type Movie struct {
 	currentScene *scenes.Scene
 	scenes       map[string]*scenes.Scene
+
+	transition      Transition
+	transitionTicks int
+	previousScene   *scenes.Scene
+	tick            int
+	from            *ebiten.Image
+	to              *ebiten.Image
 }
 
 // New creates a new movie
@@ -31,10 +39,7 @@ func FromJSON(spriteMap sprites.SpriteMap, data string, parameters map[string]in
 	if err != nil {
 		return nil, err
 	}
-	movie := Movie{
-		currentScene: &scenes.Scene{},
-		scenes:       map[string]*scenes.Scene{},
-	}
+	movie := New()
 	for _, sceneJSON := range sceneJSONs {
 		scene, err := scenes.FromJSON(spriteMap, sceneJSON, parameters)
 		if err != nil {
@@ -42,7 +47,7 @@ func FromJSON(spriteMap sprites.SpriteMap, data string, parameters map[string]in
 		}
 		movie.Add(scene)
 	}
-	return &movie, nil
+	return movie, nil
 }
 
 // Add adds a scene to the movie
@@ -53,9 +58,63 @@ func (m *Movie) Add(scene *scenes.Scene) {
 	}
 }
 
+// SetParameters lays out all scenes again using new parameters, for
+// instance after a resize, the handlers of the clips that remain are kept
+func (m *Movie) SetParameters(parameters map[string]interface{}) error {
+	for _, scene := range m.scenes {
+		if err := scene.SetParameters(parameters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetScene gets a scene from the movie
+func (m *Movie) GetScene(scene string) (*scenes.Scene, error) {
+	s, ok := m.scenes[scene]
+	if !ok {
+		return nil, fmt.Errorf("GetScene: scene '%s' not found", scene)
+	}
+	return s, nil
+}
+
+// GotoScene makes the scene with the given name the current scene, using
+// the transition that is set, it calls the exit handler of the current
+// scene and the enter handler of the next scene
+func (m *Movie) GotoScene(scene string) error {
+	s, ok := m.scenes[scene]
+	if !ok {
+		return fmt.Errorf("GotoScene: scene '%s' not found", scene)
+	}
+	if s == m.currentScene {
+		return nil
+	}
+	m.previousScene = nil
+	if m.currentScene != nil {
+		m.currentScene.Exit()
+		if m.transition != Cut && m.transitionTicks > 0 {
+			m.previousScene = m.currentScene
+			m.tick = 0
+		}
+	}
+	m.currentScene = s
+	s.Enter()
+	return nil
+}
+
+// GetSceneName gets the name of the current scene
+func (m *Movie) GetSceneName() string {
+	if m.currentScene == nil {
+		return ""
+	}
+	return m.currentScene.GetName()
+}
+
 // Draw draws the movie
 func (m *Movie) Draw(screen *ebiten.Image) {
-	if m.currentScene != nil {
+	if m.previousScene != nil {
+		m.drawTransition(screen)
+	} else if m.currentScene != nil {
 		m.currentScene.Draw(screen)
 	}
 }
@@ -63,11 +122,25 @@ func (m *Movie) Draw(screen *ebiten.Image) {
 // Update updates the movie
 func (m *Movie) Update() (err error) {
 	if m.currentScene != nil {
+		m.currentScene.Animate()
+	}
+	if m.previousScene != nil {
+		m.updateTransition()
+	} else if m.currentScene != nil {
 		err = m.currentScene.Update()
 	}
 	return err
 }
 
+// GetLayer gets a layer of a scene from the movie
+func (m *Movie) GetLayer(scene, layer string) (*layers.Layer, error) {
+	s, ok := m.scenes[scene]
+	if !ok {
+		return nil, fmt.Errorf("GetLayer: scene '%s' not found", scene)
+	}
+	return s.GetLayer(layer)
+}
+
 // GetClip gets a clip from the movie
 func (m *Movie) GetClip(scene, layer, clip string) (*clips.Clip, error) {
 	return m.getClip(scene, layer, clip, 0)
@@ -0,0 +1,84 @@
+package movies
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Transition is the way the movie changes from one scene to the next
+type Transition int
+
+const (
+	// Cut shows the next scene immediately
+	Cut Transition = iota
+	// Fade blends the next scene over the previous scene
+	Fade
+	// SlideLeft moves the next scene in from the right
+	SlideLeft
+	// SlideRight moves the next scene in from the left
+	SlideRight
+	// SlideUp moves the next scene in from the bottom
+	SlideUp
+	// SlideDown moves the next scene in from the top
+	SlideDown
+)
+
+// SetTransition sets the transition and its length in ticks that GotoScene
+// uses
+func (m *Movie) SetTransition(transition Transition, ticks int) {
+	m.transition = transition
+	m.transitionTicks = ticks
+}
+
+// IsTransitioning reports whether a transition is in progress, during a
+// transition no scene is updated
+func (m *Movie) IsTransitioning() bool {
+	return m.previousScene != nil
+}
+
+// updateTransition advances the transition by a tick
+func (m *Movie) updateTransition() {
+	m.tick++
+	if m.tick >= m.transitionTicks {
+		m.previousScene = nil
+	}
+}
+
+// drawTransition draws the previous and the current scene on offscreen
+// images and combines them on the screen
+func (m *Movie) drawTransition(screen *ebiten.Image) {
+	size := screen.Bounds().Size()
+	if m.from == nil || m.from.Bounds().Size() != size {
+		if m.from != nil {
+			m.from.Dispose()
+			m.to.Dispose()
+		}
+		m.from = ebiten.NewImage(size.X, size.Y)
+		m.to = ebiten.NewImage(size.X, size.Y)
+	}
+	m.from.Clear()
+	m.previousScene.Draw(m.from)
+	m.to.Clear()
+	m.currentScene.Draw(m.to)
+	progress := float64(m.tick) / float64(m.transitionTicks)
+	width, height := float64(size.X), float64(size.Y)
+	dx, dy := 0.0, 0.0
+	switch m.transition {
+	case SlideLeft:
+		dx = -width
+	case SlideRight:
+		dx = width
+	case SlideUp:
+		dy = -height
+	case SlideDown:
+		dy = height
+	}
+	from := &ebiten.DrawImageOptions{}
+	from.GeoM.Translate(dx*progress, dy*progress)
+	screen.DrawImage(m.from, from)
+	to := &ebiten.DrawImageOptions{}
+	to.GeoM.Translate(-dx*(1-progress), -dy*(1-progress))
+	if m.transition == Fade {
+		to.ColorScale.ScaleAlpha(float32(progress))
+	}
+	screen.DrawImage(m.to, to)
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/replay"
+)
+
+// replayHeight is the height of the playback controls below the board
+const replayHeight = 56
+
+var replaySpeeds = []float64{0.5, 1, 2, 4, 8}
+
+// loadReplay reads a replay file and shows its game in replay mode
+func (g *game) loadReplay(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	r, err := replay.Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	p, err := replay.NewPlayer(r)
+	if err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	c := g.c
+	c.width = r.Header.Config.Width
+	c.height = r.Header.Config.Height
+	c.bombs = r.Header.Config.Mines
+	c.placement = r.Header.Config.Placement
+	c.questions = r.Header.Config.Questions
+	c.practice = r.Header.Config.Practice
+	c.tps = r.Header.TPS
+	if err := c.validate(); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	if c.tps < 10 || c.tps > 240 {
+		return fmt.Errorf("%s: %d ticks per second is not in range 10-240", filename, c.tps)
+	}
+	g.c = c
+	g.setBoard(p.Board())
+	g.player = p
+	g.playing = true
+	g.speed = 1
+	return nil
+}
+
+// updateReplay plays the replay at the chosen speed instead of taking input
+func (g *game) updateReplay() {
+	g.handleReplayKeys()
+	if g.playing {
+		g.replayTicks += replaySpeeds[g.speed]
+		for g.replayTicks >= 1 && g.playing {
+			g.replayTicks--
+			if err := g.player.Tick(); err != nil {
+				log.Printf("replay stopped: %v\n", err)
+				g.playing = false
+			}
+			if g.player.Done() {
+				g.playing = false
+			}
+		}
+	}
+	g.syncReplay()
+}
+
+func (g *game) handleReplayKeys() {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.toggleReplay()
+	}
+	if isKeyRepeated(ebiten.KeyArrowLeft) {
+		g.seekReplay(g.player.Position() - 1)
+	}
+	if isKeyRepeated(ebiten.KeyArrowRight) {
+		g.seekReplay(g.player.Position() + 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyHome) {
+		g.seekReplay(0)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnd) {
+		g.seekReplay(g.player.Len())
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) {
+		g.speed = clamp(g.speed-1, 0, len(replaySpeeds)-1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) {
+		g.speed = clamp(g.speed+1, 0, len(replaySpeeds)-1)
+	}
+}
+
+func (g *game) toggleReplay() {
+	if g.player.Done() {
+		g.seekReplay(0)
+	}
+	g.playing = !g.playing
+}
+
+// seekReplay jumps to a position in the replay and pauses it
+func (g *game) seekReplay(position int) {
+	position = clamp(position, 0, g.player.Len())
+	g.playing = false
+	g.replayTicks = 0
+	if err := g.player.Seek(position); err != nil {
+		log.Printf("replay stopped: %v\n", err)
+	}
+	g.syncReplay()
+}
+
+// syncReplay shows the board, the pressed cells and the cursor of the player
+func (g *game) syncReplay() {
+	g.board = g.player.Board()
+	g.clearPressed()
+	x, y, chord, ok := g.player.Pressed()
+	g.button = buttonPlaying
+	if ok && !g.isOver() {
+		g.button = buttonEvaluate
+		g.pressed[y][x] = true
+		if chord {
+			g.board.ForEachNeighbour(x, y, func(x, y int) {
+				if !g.board.Cell(x, y).Marked {
+					g.pressed[y][x] = true
+				}
+			})
+		}
+	}
+	switch g.board.State() {
+	case board.Won:
+		g.button = buttonWon
+	case board.Lost:
+		g.button = buttonLost
+	}
+	g.cursor = true
+	g.cursorX, g.cursorY = g.player.Cursor()
+}
+
+func (g *game) setReplayHandlers() {
+	prev := g.getLayerClips("game", "replay", "prev")[0]
+	g.onClick(prev, func() {
+		g.seekReplay(g.player.Position() - 1)
+	})
+	prev.OnLongPress(func() {
+		g.pressedClip = nil
+		g.seekReplay(g.player.Position() - 10)
+	})
+	next := g.getLayerClips("game", "replay", "next")[0]
+	g.onClick(next, func() {
+		g.seekReplay(g.player.Position() + 1)
+	})
+	next.OnLongPress(func() {
+		g.pressedClip = nil
+		g.seekReplay(g.player.Position() + 10)
+	})
+	g.onClick(g.getLayerClips("game", "replay", "play")[0], func() {
+		g.toggleReplay()
+	})
+	g.onClick(g.getLayerClips("game", "replay", "slower")[0], func() {
+		g.speed = clamp(g.speed-1, 0, len(replaySpeeds)-1)
+	})
+	g.onClick(g.getLayerClips("game", "replay", "faster")[0], func() {
+		g.speed = clamp(g.speed+1, 0, len(replaySpeeds)-1)
+	})
+	seek := g.getLayerClips("game", "replay", "seek")
+	for i := range seek {
+		i := i
+		g.onClick(seek[i], func() {
+			g.seekReplay(g.seekPosition(i, len(seek)))
+		})
+	}
+}
+
+// seekPosition gets the position that a segment of the seek bar jumps to
+func (g *game) seekPosition(segment, segments int) int {
+	if segments < 2 {
+		return 0
+	}
+	return segment * g.player.Len() / (segments - 1)
+}
+
+func (g *game) setReplay() {
+	if g.player == nil {
+		return
+	}
+	play := "[>]"
+	if g.playing {
+		play = "[||]"
+	}
+	g.getLayerClips("game", "replay", "play")[0].SetText(play)
+	g.getLayerClips("game", "replay", "speed")[0].SetText(strings.TrimSuffix(fmt.Sprintf("%.1f", replaySpeeds[g.speed]), ".0") + "x")
+	ms := g.board.Elapsed().Milliseconds()
+	g.getLayerClips("game", "replay", "position")[0].SetText(fmt.Sprintf("Action %d/%d  %d.%03ds", g.player.Position(), g.player.Len(), ms/1000, ms%1000))
+	seek := g.getLayerClips("game", "replay", "seek")
+	current := 0
+	for i := range seek {
+		if g.seekPosition(i, len(seek)) <= g.player.Position() {
+			current = i
+		}
+	}
+	for i, segment := range seek {
+		text := "--"
+		if i < current {
+			text = "=="
+		} else if i == current {
+			text = "<>"
+		}
+		segment.SetText(text)
+	}
+}
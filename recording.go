@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/replay"
+	"github.com/mevdschee/ebiten-mines/storage"
+)
+
+// record adds an action on a cell to the replay of the current game
+func (g *game) record(action replay.Action, x, y int) {
+	g.replay.Add(g.board, action, x, y)
+}
+
+// saveReplay ends the replay of the current game and stores it under
+// "replays" with the date and time of the game in milliseconds as name, so
+// that games started in the same second do not overwrite each other
+func (g *game) saveReplay() {
+	g.replay.AddEnd(g.board)
+	data, err := g.replay.Marshal()
+	if err == nil {
+		date := g.replay.Header.Date.Local()
+		name := fmt.Sprintf("replays/%s-%03d.jsonl", date.Format("20060102-150405"), date.Nanosecond()/int(time.Millisecond))
+		err = storage.Save(name, data)
+		if err == nil {
+			log.Printf("replay saved as %s\n", name)
+		}
+	}
+	if err != nil {
+		log.Printf("could not save replay: %v\n", err)
+	}
+}
+
+// replayPending reports whether the current game was started but its
+// replay is not saved yet, because it was left before it ended or after
+// hitting a mine that could still be undone
+func (g *game) replayPending() bool {
+	switch g.board.State() {
+	case board.Playing:
+		return true
+	case board.Lost:
+		return g.board.CanUndo()
+	}
+	return false
+}
+
+// newReplay starts the replay of the current game
+func (g *game) newReplay() *replay.Replay {
+	return replay.New(g.board.Config(), g.c.tps, g.c.name)
+}
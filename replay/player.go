@@ -0,0 +1,199 @@
+package replay
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mevdschee/ebiten-mines/board"
+)
+
+// MaxDuration is the longest game that is played, the player advances one
+// tick at a time and a later event would keep it busy for too long
+const MaxDuration = 24 * time.Hour
+
+// Player plays the events of a replay on a board, tick by tick
+type Player struct {
+	replay   *Replay
+	board    *board.Board
+	index    int
+	pressing bool
+	chord    bool
+	x        int
+	y        int
+}
+
+// NewPlayer creates a player that starts before the first event
+func NewPlayer(r *Replay) (*Player, error) {
+	if err := r.Header.Config.Validate(); err != nil {
+		return nil, err
+	}
+	if r.Header.TPS < 1 {
+		return nil, fmt.Errorf("Ticks per second must be at least 1")
+	}
+	for i, e := range r.Events {
+		if e.Tick < 0 {
+			return nil, fmt.Errorf("Event %d is at negative tick %d", i+1, e.Tick)
+		}
+		if i > 0 && e.Tick < r.Events[i-1].Tick {
+			return nil, fmt.Errorf("Event %d at tick %d is before the previous event", i+1, e.Tick)
+		}
+		if float64(e.Tick) > MaxDuration.Seconds()*float64(r.Header.TPS) {
+			return nil, fmt.Errorf("Event %d at tick %d is more than %d hours into the game", i+1, e.Tick, int(MaxDuration.Hours()))
+		}
+	}
+	p := &Player{replay: r}
+	p.rewind()
+	return p, nil
+}
+
+func (p *Player) rewind() {
+	p.board = board.New(p.replay.Header.Config)
+	p.index = 0
+	p.pressing = false
+	p.x, p.y = 0, 0
+}
+
+// Board gets the board, it is replaced when seeking backwards
+func (p *Player) Board() *board.Board {
+	return p.board
+}
+
+// Position gets the number of events that are played
+func (p *Player) Position() int {
+	return p.index
+}
+
+// Len gets the number of events in the replay
+func (p *Player) Len() int {
+	return len(p.replay.Events)
+}
+
+// Done reports whether all events are played
+func (p *Player) Done() bool {
+	return p.index == len(p.replay.Events)
+}
+
+// Cursor gets the cell of the last played event
+func (p *Player) Cursor() (x, y int) {
+	return p.x, p.y
+}
+
+// Pressed gets the pressed cell, whether its neighbours are pressed as in
+// a chord and whether any cell is pressed
+func (p *Player) Pressed() (x, y int, chord, ok bool) {
+	return p.x, p.y, p.chord, p.pressing
+}
+
+func (p *Player) duration() time.Duration {
+	return time.Second / time.Duration(p.replay.Header.TPS)
+}
+
+// Tick plays the events of the current tick and advances the clock
+func (p *Player) Tick() error {
+	for !p.Done() && p.replay.Events[p.index].Tick <= p.board.Ticks() {
+		if err := p.apply(); err != nil {
+			return err
+		}
+	}
+	if p.Done() || p.board.State() == board.Playing {
+		p.board.Tick(p.duration())
+		return nil
+	}
+	e := p.replay.Events[p.index]
+	return fmt.Errorf("Event %d at tick %d can not happen while %s at tick %d", p.index+1, e.Tick, p.board.State(), p.board.Ticks())
+}
+
+// Seek plays the events up to the given position, seeking backwards
+// replays the game from the start
+func (p *Player) Seek(position int) error {
+	if position < 0 || position > len(p.replay.Events) {
+		return fmt.Errorf("Position %d is not in range 0-%d", position, len(p.replay.Events))
+	}
+	if position < p.index {
+		p.rewind()
+	}
+	for p.index < position {
+		e := p.replay.Events[p.index]
+		if e.Tick <= p.board.Ticks() {
+			if err := p.apply(); err != nil {
+				return err
+			}
+			continue
+		}
+		if p.board.State() != board.Playing {
+			return fmt.Errorf("Event %d at tick %d can not happen while %s at tick %d", p.index+1, e.Tick, p.board.State(), p.board.Ticks())
+		}
+		p.board.Tick(p.duration())
+	}
+	return nil
+}
+
+// Run plays all remaining events
+func (p *Player) Run() error {
+	return p.Seek(len(p.replay.Events))
+}
+
+// apply plays the next event and checks that it is allowed by the rules
+func (p *Player) apply() error {
+	e := p.replay.Events[p.index]
+	if e.Tick < p.board.Ticks() {
+		return fmt.Errorf("Event %d at tick %d is before tick %d", p.index+1, e.Tick, p.board.Ticks())
+	}
+	state := p.board.State()
+	if e.Action == End {
+		p.pressing = false
+		p.index++
+		return nil
+	}
+	if p.index > 0 && p.replay.Events[p.index-1].Action == End {
+		return fmt.Errorf("Event %d is after the end", p.index+1)
+	}
+	if !p.board.Contains(e.X, e.Y) {
+		return fmt.Errorf("Event %d at %d,%d is not on the board", p.index+1, e.X, e.Y)
+	}
+	if (state == board.Won || state == board.Lost) && e.Action != Undo {
+		return fmt.Errorf("Event %d is %s on %d,%d after the game is %s", p.index+1, e.Action, e.X, e.Y, state)
+	}
+	cell := p.board.Cell(e.X, e.Y)
+	switch e.Action {
+	case Press, ChordPress:
+		p.pressing = true
+		p.chord = e.Action == ChordPress || cell.Open
+	case Release:
+		p.pressing = false
+	case Open:
+		if cell.Open {
+			return fmt.Errorf("Event %d opens %d,%d that is already open", p.index+1, e.X, e.Y)
+		}
+		p.board.Open(e.X, e.Y)
+	case Flag:
+		if cell.Open {
+			return fmt.Errorf("Event %d flags %d,%d that is open", p.index+1, e.X, e.Y)
+		}
+		p.board.ToggleFlag(e.X, e.Y)
+	case Chord:
+		if !cell.Open {
+			return fmt.Errorf("Event %d chords %d,%d that is closed", p.index+1, e.X, e.Y)
+		}
+		p.board.Chord(e.X, e.Y)
+	case Hint:
+		p.board.Hint()
+	case Heatmap:
+		p.board.Probabilities()
+	case AutoFlag:
+		p.board.AutoFlag()
+	case AutoOpen:
+		p.board.AutoOpen()
+	case Undo:
+		if !p.board.Undo() {
+			return fmt.Errorf("Event %d undoes a move that can not be undone", p.index+1)
+		}
+	default:
+		return fmt.Errorf("Event %d has unknown action '%s'", p.index+1, e.Action)
+	}
+	if e.Action.isOnCell() {
+		p.x, p.y = e.X, e.Y
+	}
+	p.index++
+	return nil
+}
@@ -0,0 +1,113 @@
+package replay
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mevdschee/ebiten-mines/board"
+)
+
+// play wins a game on a board the way the game records it: a press and a
+// later release before every open, with the board ticking in between
+func play(t *testing.T, config board.Config, tps int) (*Replay, *board.Board) {
+	r := New(config, tps, "test")
+	b := board.New(config)
+	tick := func(n int) {
+		for i := 0; i < n; i++ {
+			b.Tick(time.Second / time.Duration(tps))
+		}
+	}
+	open := func(x, y int) {
+		r.Add(b, Press, x, y)
+		tick(3)
+		r.Add(b, Release, x, y)
+		r.Add(b, Open, x, y)
+		b.Open(x, y)
+		tick(10)
+	}
+	open(config.Width/2, config.Height/2)
+	for y := 0; y < b.Height(); y++ {
+		for x := 0; x < b.Width(); x++ {
+			if cell := b.Cell(x, y); !cell.Open && !cell.Mine {
+				open(x, y)
+			}
+		}
+	}
+	if b.State() != board.Won {
+		t.Fatalf("state is %s, expected won", b.State())
+	}
+	r.AddEnd(b)
+	return r, b
+}
+
+func TestPlayerRoundTrip(t *testing.T) {
+	config := board.Config{Width: 9, Height: 9, Mines: 10, Seed: 42}
+	r, b := play(t, config, 30)
+	data, err := r.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.Events, r.Events) {
+		t.Fatal("parsed events differ from the recorded events")
+	}
+	p, err := NewPlayer(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Done() {
+		t.Fatal("player is not done after running")
+	}
+	if !reflect.DeepEqual(p.Board().Snapshot(), b.Snapshot()) {
+		t.Fatalf("played board differs:\n%+v\n%+v", p.Board().Snapshot(), b.Snapshot())
+	}
+	if p.Board().Metrics() != b.Metrics() {
+		t.Fatalf("played metrics are %+v, expected %+v", p.Board().Metrics(), b.Metrics())
+	}
+}
+
+func TestPlayerSeek(t *testing.T) {
+	r, b := play(t, board.Config{Width: 9, Height: 9, Mines: 10, Seed: 7, Placement: board.Opening}, 60)
+	p, err := NewPlayer(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, position := range []int{p.Len() / 2, 3, p.Len(), 0, p.Len()} {
+		if err := p.Seek(position); err != nil {
+			t.Fatal(err)
+		}
+		if p.Position() != position {
+			t.Fatalf("position is %d after seeking to %d", p.Position(), position)
+		}
+	}
+	if !reflect.DeepEqual(p.Board().Snapshot(), b.Snapshot()) {
+		t.Fatal("board differs after seeking back and forth")
+	}
+}
+
+func TestPlayerRejectsIllegalEvents(t *testing.T) {
+	r, _ := play(t, board.Config{Width: 9, Height: 9, Mines: 10, Seed: 42}, 30)
+	r.Events[len(r.Events)-2].Action = Chord
+	p, err := NewPlayer(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Run(); err == nil {
+		t.Fatal("chord on a closed cell is played")
+	}
+}
+
+func TestPlayerRejectsLateEvents(t *testing.T) {
+	r, _ := play(t, board.Config{Width: 9, Height: 9, Mines: 10, Seed: 42}, 30)
+	r.Events[len(r.Events)-1].Tick = 100000000000
+	if _, err := NewPlayer(r); err == nil {
+		t.Fatal("event after the longest game is played")
+	}
+}
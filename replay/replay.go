@@ -0,0 +1,198 @@
+// Package replay records the actions of a game as JSON Lines.
+//
+// The first line is the header with the board config, the ticks per second
+// and the player, every following line is an event:
+//
+//	{"version":1,"config":{"width":9,"height":9,"mines":10,"seed":42,"placement":"classic","questions":false},"tps":30,"name":"player","date":"2024-04-07T12:00:00Z"}
+//	{"tick":0,"action":"press","x":4,"y":4}
+//	{"tick":0,"action":"open","x":4,"y":4}
+//	{"tick":31,"action":"flag","x":2,"y":3}
+//	{"tick":52,"action":"chord","x":3,"y":3}
+//	{"tick":370,"action":"end","result":"won","elapsed":12333,"3bv":23,"mines":[5,17,22]}
+//
+// The tick is the number of ticks the game has been playing, it does not
+// advance while waiting for the first click or while paused. The actions
+// "open", "flag" and "chord" are applied to the board at the given cell,
+// "press", "chordpress" and "release" only show the pressed cells, and
+// "hint" and "heatmap" ask the board for help, which counts as a hint. The
+// assistants "autoflag" and "autoopen" act on the whole board and "undo"
+// takes back the last move in practice mode, also after hitting a mine. The
+// "end" event holds the result, the elapsed time in milliseconds, the 3BV
+// and the mines as cell indices (y*width+x).
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mevdschee/ebiten-mines/board"
+)
+
+// Version is the version of the format that is written
+const Version = 1
+
+// DefaultTPS is the number of ticks per second that the game runs at
+// unless it is changed, verified replays must be recorded at it
+const DefaultTPS = 30
+
+// Action is the kind of an event
+type Action string
+
+const (
+	// Press shows a pressed cell, and its neighbours when it is open
+	Press Action = "press"
+	// ChordPress shows a pressed cell and its neighbours
+	ChordPress Action = "chordpress"
+	// Release clears the pressed cells
+	Release Action = "release"
+	// Open opens a cell
+	Open Action = "open"
+	// Flag toggles the flag of a cell
+	Flag Action = "flag"
+	// Chord opens the neighbours of a cell
+	Chord Action = "chord"
+	// Hint asks for a cell that is certainly safe
+	Hint Action = "hint"
+	// Heatmap asks for the chance of a mine on every cell
+	Heatmap Action = "heatmap"
+	// AutoFlag flags the cells that are certainly mines
+	AutoFlag Action = "autoflag"
+	// AutoOpen opens the cells next to numbers satisfied by certain mines
+	AutoOpen Action = "autoopen"
+	// Undo takes back the last move in practice mode
+	Undo Action = "undo"
+	// End records the result of the game
+	End Action = "end"
+)
+
+// isOnCell reports whether the action is done on the cell of the event,
+// instead of on the whole board
+func (a Action) isOnCell() bool {
+	return a != Heatmap && a != AutoFlag && a != AutoOpen && a != Undo && a != End
+}
+
+// Header describes the game that is replayed
+type Header struct {
+	Version int          `json:"version"`
+	Config  board.Config `json:"config"`
+	TPS     int          `json:"tps"`
+	Name    string       `json:"name"`
+	Date    time.Time    `json:"date"`
+}
+
+// Event is an action on a cell at a tick
+type Event struct {
+	Tick    int         `json:"tick"`
+	Action  Action      `json:"action"`
+	X       int         `json:"x"`
+	Y       int         `json:"y"`
+	Result  board.State `json:"result,omitempty"`
+	Elapsed int64       `json:"elapsed,omitempty"`
+	ThreeBV int         `json:"3bv,omitempty"`
+	Mines   []int       `json:"mines,omitempty"`
+}
+
+// Replay is a recorded game
+type Replay struct {
+	Header Header  `json:"header"`
+	Events []Event `json:"events"`
+}
+
+// New creates a new replay for a board
+func New(config board.Config, tps int, name string) *Replay {
+	return &Replay{
+		Header: Header{
+			Version: Version,
+			Config:  config,
+			TPS:     tps,
+			Name:    name,
+			Date:    time.Now().UTC(),
+		},
+		Events: []Event{},
+	}
+}
+
+// Add adds an action on a cell of the board at its current tick
+func (r *Replay) Add(b *board.Board, action Action, x, y int) {
+	r.Events = append(r.Events, Event{
+		Tick:   b.Ticks(),
+		Action: action,
+		X:      x,
+		Y:      y,
+	})
+}
+
+// AddEnd adds the result of the game on the board
+func (r *Replay) AddEnd(b *board.Board) {
+	mines := []int{}
+	for y := 0; y < b.Height(); y++ {
+		for x := 0; x < b.Width(); x++ {
+			if b.Cell(x, y).Mine {
+				mines = append(mines, y*b.Width()+x)
+			}
+		}
+	}
+	r.Events = append(r.Events, Event{
+		Tick:    b.Ticks(),
+		Action:  End,
+		Result:  b.State(),
+		Elapsed: b.Elapsed().Milliseconds(),
+		ThreeBV: b.Metrics().ThreeBV,
+		Mines:   mines,
+	})
+}
+
+// Marshal writes the replay as JSON Lines
+func (r *Replay) Marshal() ([]byte, error) {
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	err := encoder.Encode(r.Header)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range r.Events {
+		err = encoder.Encode(event)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+// Parse reads a replay from JSON Lines
+func Parse(data []byte) (*Replay, error) {
+	r := &Replay{Events: []Event{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var err error
+		if line == 1 {
+			err = json.Unmarshal(scanner.Bytes(), &r.Header)
+		} else {
+			event := Event{}
+			err = json.Unmarshal(scanner.Bytes(), &event)
+			r.Events = append(r.Events, event)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if line == 0 {
+		return nil, fmt.Errorf("Replay is empty")
+	}
+	if r.Header.Version != Version {
+		return nil, fmt.Errorf("Replay version %d is not supported", r.Header.Version)
+	}
+	return r, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/replay"
+	"github.com/mevdschee/ebiten-mines/storage"
+)
+
+const savegameFilename = "savegame.json"
+
+// savegameVersion is incremented on every incompatible change of the format
+const savegameVersion = 1
+
+// savegame is the game in progress as stored in "savegame.json":
+//
+//	{
+//	  "version": 1,
+//	  "board": {
+//	    "config": {"width": 9, "height": 9, "mines": 10, "seed": 42,
+//	               "placement": "classic", "questions": false},
+//	    "state": "playing",
+//	    "remaining": 9,
+//	    "closed": 58,
+//	    "ticks": 370,
+//	    "elapsed": 12345,
+//	    "leftClicks": 12,
+//	    "rightClicks": 3,
+//	    "chordClicks": 2,
+//	    "hints": 1,
+//	    "assists": 4,
+//	    "undos": 1,
+//	    "cells": [[0, 18, 33, ...], ...]
+//	  },
+//	  "replay": {
+//	    "header": {"version": 1, "config": {...}, "tps": 30, ...},
+//	    "events": [{"tick": 0, "action": "open", "x": 4, "y": 4}, ...]
+//	  }
+//	}
+//
+// Remaining is the mine counter, closed is the number of closed cells,
+// ticks is the number of game ticks played, the elapsed time is in
+// milliseconds, the clicks are counted per kind and hints counts the times
+// help was asked, assists the cells that the assistants flagged or opened
+// and undos the moves undone in practice mode (they are left out when
+// zero). Each cell has bit 1 set for a mine, bit 2 for open, bit 4 for a
+// flag, bit 8 for a question mark, and holds the number of neighbouring
+// mines multiplied by 16. The replay holds the actions so far in the format
+// of the replay package, it is optional, a game is only resumed at the
+// ticks per second of its replay.
+type savegame struct {
+	Version int            `json:"version"`
+	Board   board.Snapshot `json:"board"`
+	Replay  *replay.Replay `json:"replay,omitempty"`
+}
+
+// saveGame stores the game when it is in progress and removes the stored
+// game otherwise, so that a finished game is never resumed, the replay of
+// a practice game that is left on a mine is saved instead and it counts as
+// lost
+func (g *game) saveGame() {
+	if g.board.State() != board.Playing {
+		if g.replayPending() {
+			g.recordStats()
+			g.saveReplay()
+		}
+		if err := storage.Remove(savegameFilename); err != nil {
+			log.Printf("could not remove saved game: %v\n", err)
+		}
+		return
+	}
+	data, err := json.Marshal(savegame{
+		Version: savegameVersion,
+		Board:   g.board.Snapshot(),
+		Replay:  g.replay,
+	})
+	if err == nil {
+		err = storage.Save(savegameFilename, data)
+	}
+	if err != nil {
+		log.Printf("could not save game: %v\n", err)
+		return
+	}
+	log.Println("game saved")
+}
+
+// loadGame resumes the stored game and reports whether that succeeded
+func (g *game) loadGame() bool {
+	data, err := storage.Load(savegameFilename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false
+	}
+	if err == nil {
+		err = g.restoreGame(data)
+	}
+	if err != nil {
+		log.Printf("could not load saved game: %v\n", err)
+		return false
+	}
+	return true
+}
+
+func (g *game) restoreGame(data []byte) error {
+	s := savegame{}
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	if s.Version != savegameVersion {
+		return fmt.Errorf("version %d is not supported", s.Version)
+	}
+	if s.Replay != nil && s.Replay.Header.TPS != g.c.tps {
+		return fmt.Errorf("game was played at %d ticks per second instead of %d", s.Replay.Header.TPS, g.c.tps)
+	}
+	b, err := board.Restore(s.Board)
+	if err != nil {
+		return err
+	}
+	c := g.c
+	c.width = s.Board.Config.Width
+	c.height = s.Board.Config.Height
+	c.bombs = s.Board.Config.Mines
+	c.placement = s.Board.Config.Placement
+	c.questions = s.Board.Config.Questions
+	c.practice = s.Board.Config.Practice
+	if err := c.validate(); err != nil {
+		return err
+	}
+	g.c = c
+	g.setBoard(b)
+	if s.Replay != nil {
+		g.replay = s.Replay
+	}
+	return nil
+}
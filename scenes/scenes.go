@@ -11,9 +11,11 @@ import (
 
 // Scene is a set of layers
 type Scene struct {
-	name   string
-	layers map[string]*layers.Layer
-	order  []string
+	name    string
+	layers  map[string]*layers.Layer
+	order   []string
+	onEnter func()
+	onExit  func()
 }
 
 // SceneJSON is a set of layers in JSON
@@ -58,11 +60,96 @@ func FromJSON(spriteMap sprites.SpriteMap, sceneJSON SceneJSON, parameters map[s
 	return &scene, nil
 }
 
-// Add adds a layers to the scene
+// Add adds a layer on top of the other layers of the scene, a layer with
+// the same name is replaced in its place
 func (s *Scene) Add(layer *layers.Layer) {
 	name := layer.GetName()
+	if _, ok := s.layers[name]; !ok {
+		s.order = append(s.order, name)
+	}
 	s.layers[name] = layer
-	s.order = append(s.order, name)
+}
+
+// Remove removes a layer from the scene
+func (s *Scene) Remove(layer string) error {
+	i := s.index(layer)
+	if i < 0 {
+		return fmt.Errorf("Remove: layer '%s' not found", layer)
+	}
+	delete(s.layers, layer)
+	s.order = append(s.order[:i], s.order[i+1:]...)
+	return nil
+}
+
+// MoveLayer moves a layer to a position in the drawing order, where 0 is
+// the bottom layer
+func (s *Scene) MoveLayer(layer string, index int) error {
+	i := s.index(layer)
+	if i < 0 {
+		return fmt.Errorf("MoveLayer: layer '%s' not found", layer)
+	}
+	if index < 0 || index >= len(s.order) {
+		return fmt.Errorf("MoveLayer: index %d is out of range", index)
+	}
+	s.order = append(s.order[:i], s.order[i+1:]...)
+	s.order = append(s.order[:index], append([]string{layer}, s.order[index:]...)...)
+	return nil
+}
+
+// GetLayer gets a layer from the scene
+func (s *Scene) GetLayer(layer string) (*layers.Layer, error) {
+	if l, ok := s.layers[layer]; ok {
+		return l, nil
+	}
+	return nil, fmt.Errorf("GetLayer: layer '%s' not found", layer)
+}
+
+// GetOrder gets the names of the layers from the bottom to the top
+func (s *Scene) GetOrder() []string {
+	return append([]string{}, s.order...)
+}
+
+func (s *Scene) index(layer string) int {
+	for i, name := range s.order {
+		if name == layer {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetParameters lays out the layers of the scene again using new parameters
+func (s *Scene) SetParameters(parameters map[string]interface{}) error {
+	for _, name := range s.order {
+		if err := s.layers[name].SetParameters(parameters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnEnter sets the handler that is called when the movie goes to the scene
+func (s *Scene) OnEnter(handler func()) {
+	s.onEnter = handler
+}
+
+// OnExit sets the handler that is called when the movie leaves the scene
+func (s *Scene) OnExit(handler func()) {
+	s.onExit = handler
+}
+
+// Enter calls the enter handler of the scene
+func (s *Scene) Enter() {
+	if s.onEnter != nil {
+		s.onEnter()
+	}
+}
+
+// Exit calls the exit handler of the scene
+func (s *Scene) Exit() {
+	if s.onExit != nil {
+		s.onExit()
+	}
 }
 
 // Draw draws the scene
@@ -72,9 +159,25 @@ func (s *Scene) Draw(screen *ebiten.Image) {
 	}
 }
 
-// Update updates the scene
-func (s *Scene) Update() (err error) {
+// Animate advances the animations of the layers by a tick
+func (s *Scene) Animate() {
 	for _, name := range s.order {
+		s.layers[name].Animate()
+	}
+}
+
+// Update updates the scene, only the layers from the top visible modal
+// layer up are updated
+func (s *Scene) Update() (err error) {
+	start := 0
+	for i := len(s.order) - 1; i >= 0; i-- {
+		layer := s.layers[s.order[i]]
+		if layer.IsVisible() && layer.IsModal() {
+			start = i
+			break
+		}
+	}
+	for _, name := range s.order[start:] {
 		err = s.layers[name].Update()
 		if err != nil {
 			break
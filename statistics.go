@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/stats"
+)
+
+// statsSuffixes are added to the stats key of games in which help was used,
+// the stats title shows them as markers
+var statsSuffixes = []struct {
+	suffix string
+	marker string
+}{
+	{" hints", "+H"},
+	{" assist", "+A"},
+	{" practice", "+P"},
+}
+
+func (c config) statsKey() string {
+	name := c.difficulty()
+	if name == "custom" {
+		name = fmt.Sprintf("custom %dx%d/%d", c.width, c.height, c.bombs)
+	}
+	return name
+}
+
+func (g *game) recordStats() {
+	if g.board.Undos() > 0 {
+		log.Printf("game with %d undone moves is not recorded\n", g.board.Undos())
+		return
+	}
+	won := g.board.State() == board.Won
+	m := g.board.Metrics()
+	t := stats.Time{
+		Name:     g.c.name,
+		Date:     time.Now(),
+		Duration: m.Elapsed,
+		ThreeBV:  m.ThreeBV,
+		Clicks:   m.Clicks(),
+	}
+	key := g.c.statsKey()
+	if g.board.Hints() > 0 {
+		key += statsSuffixes[0].suffix
+	}
+	if g.board.Assists() > 0 {
+		key += statsSuffixes[1].suffix
+	}
+	if g.board.Practice() {
+		key += statsSuffixes[2].suffix
+	}
+	rank := g.stats.Add(key, won, t)
+	if rank > 0 {
+		log.Printf("best time #%d: %.3f seconds, %.2f 3BV/s, %d%% efficiency\n", rank, t.Duration.Seconds(), t.ThreeBVPerSecond(), t.Efficiency())
+	}
+	if err := g.stats.Save(); err != nil {
+		log.Printf("could not save stats: %v\n", err)
+	}
+}
+
+func (g *game) statsKeys() []string {
+	keys := []string{}
+	for _, d := range difficulties {
+		keys = append(keys, d.name)
+	}
+	if g.c.difficulty() == "custom" {
+		keys = append(keys, g.c.statsKey())
+	}
+	for _, key := range g.stats.Keys() {
+		found := false
+		for _, k := range keys {
+			found = found || k == key
+		}
+		if !found {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (g *game) openStats() {
+	if err := g.movie.GotoScene("stats"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *game) stepStats(step int) {
+	keys := g.statsKeys()
+	for i, key := range keys {
+		if key == g.statsKey {
+			g.statsKey = keys[(i+step+len(keys))%len(keys)]
+			return
+		}
+	}
+	g.statsKey = keys[0]
+}
+
+func (g *game) setStatsHandlers() {
+	g.getScene("stats").OnEnter(func() {
+		g.statsKey = g.c.statsKey()
+		g.statsReset = false
+		g.pressedClip = nil
+	})
+	g.onClick(g.getClips("stats", "prev")[0], func() {
+		g.stepStats(-1)
+	})
+	g.onClick(g.getClips("stats", "next")[0], func() {
+		g.stepStats(1)
+	})
+	g.onClick(g.getClips("stats", "back")[0], func() {
+		g.openMenu()
+	})
+	g.onClick(g.getClips("stats", "reset")[0], func() {
+		if !g.statsReset {
+			g.statsReset = true
+			return
+		}
+		g.statsReset = false
+		g.stats.Reset()
+		if err := g.stats.Save(); err != nil {
+			log.Printf("could not save stats: %v\n", err)
+		}
+	})
+}
+
+// statsTitle gets the title of a stats key with markers for its suffixes
+func statsTitle(key string) string {
+	markers := ""
+	for i := len(statsSuffixes) - 1; i >= 0; i-- {
+		if strings.HasSuffix(key, statsSuffixes[i].suffix) {
+			key = strings.TrimSuffix(key, statsSuffixes[i].suffix)
+			markers = statsSuffixes[i].marker + markers
+		}
+	}
+	title := key
+	for _, d := range difficulties {
+		if d.name == key {
+			title = d.title
+		}
+	}
+	if markers != "" {
+		title += " " + markers
+	}
+	return title
+}
+
+func (g *game) setStats() {
+	if g.movie.GetSceneName() != "stats" {
+		return
+	}
+	g.getClips("stats", "title")[0].SetText(statsTitle(g.statsKey))
+	r := g.stats.Get(g.statsKey)
+	g.getClips("stats", "summary")[0].SetText(fmt.Sprintf("Played %d  Won %d (%d%%)\nStreak %d  Longest %d", r.Played, r.Won, r.WinRate(), r.Streak, r.LongestStreak))
+	width, height := g.getSize()
+	rows := (height - 88) / 16
+	columns := (width - 16) / 6
+	lines := []string{}
+	for i, t := range r.Times {
+		if i >= rows {
+			break
+		}
+		line := fmt.Sprintf("%2d %6.1fs %5.2f %-8.8s", i+1, t.Duration.Seconds(), t.ThreeBVPerSecond(), t.Name)
+		if columns >= len(line)+16 {
+			line += fmt.Sprintf(" %3d%% %s", t.Efficiency(), t.Date.Format("2006-01-02"))
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No wins yet")
+	}
+	g.getClips("stats", "times")[0].SetText(strings.Join(lines, "\n"))
+	reset := "Reset all"
+	if g.statsReset {
+		reset = "    Sure?"
+	}
+	g.getClips("stats", "reset")[0].SetText(reset)
+}
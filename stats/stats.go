@@ -0,0 +1,152 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/mevdschee/ebiten-mines/storage"
+)
+
+const filename = "stats.json"
+
+// MaxTimes is the number of best times that are kept per record
+const MaxTimes = 10
+
+// Time is a best time of a won game with its 3BV and number of clicks
+type Time struct {
+	Name     string        `json:"name"`
+	Date     time.Time     `json:"date"`
+	Duration time.Duration `json:"duration"`
+	ThreeBV  int           `json:"3bv"`
+	Clicks   int           `json:"clicks"`
+}
+
+// ThreeBVPerSecond gets the 3BV divided by the seconds of the time
+func (t Time) ThreeBVPerSecond() float64 {
+	if t.Duration <= 0 {
+		return 0
+	}
+	return float64(t.ThreeBV) / t.Duration.Seconds()
+}
+
+// Efficiency gets the 3BV as a percentage of the clicks
+func (t Time) Efficiency() int {
+	if t.Clicks == 0 {
+		return 0
+	}
+	return t.ThreeBV * 100 / t.Clicks
+}
+
+// Record holds the results of the games with the same settings
+type Record struct {
+	Played        int    `json:"played"`
+	Won           int    `json:"won"`
+	Streak        int    `json:"streak"`
+	LongestStreak int    `json:"longestStreak"`
+	Times         []Time `json:"times"`
+}
+
+// Stats holds the records by key
+type Stats struct {
+	Records map[string]*Record `json:"records"`
+}
+
+// New creates empty stats
+func New() *Stats {
+	return &Stats{
+		Records: map[string]*Record{},
+	}
+}
+
+// Load reads the stats from storage, missing stats are empty
+func Load() (*Stats, error) {
+	s := New()
+	data, err := storage.Load(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, s)
+	if s.Records == nil {
+		s.Records = map[string]*Record{}
+	}
+	return s, err
+}
+
+// Save writes the stats to storage
+func (s *Stats) Save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return storage.Save(filename, data)
+}
+
+// Get gets the record for a key, the record is empty when not found
+func (s *Stats) Get(key string) Record {
+	if r, ok := s.Records[key]; ok {
+		return *r
+	}
+	return Record{}
+}
+
+// Keys gets the keys of the records in sorted order
+func (s *Stats) Keys() []string {
+	keys := []string{}
+	for key := range s.Records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Add records the result of a game, it returns the rank of the time
+// among the best times or zero when it is not one of them
+func (s *Stats) Add(key string, won bool, t Time) int {
+	r, ok := s.Records[key]
+	if !ok {
+		r = &Record{}
+		s.Records[key] = r
+	}
+	r.Played++
+	if !won {
+		r.Streak = 0
+		return 0
+	}
+	r.Won++
+	r.Streak++
+	if r.Streak > r.LongestStreak {
+		r.LongestStreak = r.Streak
+	}
+	rank := sort.Search(len(r.Times), func(i int) bool {
+		return r.Times[i].Duration > t.Duration
+	})
+	if rank >= MaxTimes {
+		return 0
+	}
+	r.Times = append(r.Times, Time{})
+	copy(r.Times[rank+1:], r.Times[rank:])
+	r.Times[rank] = t
+	if len(r.Times) > MaxTimes {
+		r.Times = r.Times[:MaxTimes]
+	}
+	return rank + 1
+}
+
+// Reset removes all records
+func (s *Stats) Reset() {
+	s.Records = map[string]*Record{}
+}
+
+// WinRate gets the percentage of played games that were won
+func (r Record) WinRate() int {
+	if r.Played == 0 {
+		return 0
+	}
+	return r.Won * 100 / r.Played
+}
@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddRanksTimes(t *testing.T) {
+	s := New()
+	for i, seconds := range []int{30, 10, 20, 20} {
+		rank := s.Add("beginner", true, Time{Duration: time.Duration(seconds) * time.Second})
+		if want := []int{1, 1, 2, 3}[i]; rank != want {
+			t.Fatalf("time %d: rank %d, want %d", i+1, rank, want)
+		}
+	}
+	r := s.Get("beginner")
+	for i, seconds := range []int{10, 20, 20, 30} {
+		if r.Times[i].Duration != time.Duration(seconds)*time.Second {
+			t.Fatalf("time %d is %v, want %ds", i+1, r.Times[i].Duration, seconds)
+		}
+	}
+}
+
+func TestAddKeepsBestTimes(t *testing.T) {
+	s := New()
+	for i := MaxTimes; i > 0; i-- {
+		s.Add("expert", true, Time{Duration: time.Duration(i) * time.Second})
+	}
+	if rank := s.Add("expert", true, Time{Duration: time.Minute}); rank != 0 {
+		t.Fatalf("slowest time has rank %d", rank)
+	}
+	if rank := s.Add("expert", true, Time{Duration: time.Second / 2}); rank != 1 {
+		t.Fatalf("fastest time has rank %d", rank)
+	}
+	r := s.Get("expert")
+	if len(r.Times) != MaxTimes {
+		t.Fatalf("%d times kept, want %d", len(r.Times), MaxTimes)
+	}
+	if last := r.Times[MaxTimes-1].Duration; last != time.Duration(MaxTimes-1)*time.Second {
+		t.Fatalf("last time is %v", last)
+	}
+}
+
+func TestAddCountsStreaks(t *testing.T) {
+	s := New()
+	for _, won := range []bool{true, true, true, false, true} {
+		s.Add("beginner", won, Time{Duration: time.Second})
+	}
+	r := s.Get("beginner")
+	if r.Played != 5 || r.Won != 4 || r.Streak != 1 || r.LongestStreak != 3 {
+		t.Fatalf("played %d, won %d, streak %d, longest %d", r.Played, r.Won, r.Streak, r.LongestStreak)
+	}
+	if r.WinRate() != 80 {
+		t.Fatalf("win rate %d%%", r.WinRate())
+	}
+	if rank := s.Add("beginner", false, Time{Duration: time.Millisecond}); rank != 0 {
+		t.Fatalf("lost game has rank %d", rank)
+	}
+}
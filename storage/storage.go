@@ -0,0 +1,52 @@
+//go:build !js
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func path(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ebiten-mines", filepath.FromSlash(name)), nil
+}
+
+// Load reads the data stored under a name, when nothing is stored the
+// error matches fs.ErrNotExist
+func Load(name string) ([]byte, error) {
+	filename, err := path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filename)
+}
+
+// Save stores the data under a name in the user's config directory
+func Save(name string, data []byte) error {
+	filename, err := path(name)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// Remove removes the data stored under a name
+func Remove(name string) error {
+	filename, err := path(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+//go:build js
+
+package storage
+
+import (
+	"fmt"
+	"io/fs"
+	"syscall/js"
+)
+
+func localStorage() (js.Value, error) {
+	storage := js.Global().Get("localStorage")
+	if storage.IsUndefined() || storage.IsNull() {
+		return storage, fmt.Errorf("localStorage is not available")
+	}
+	return storage, nil
+}
+
+// Load reads the data stored under a name, when nothing is stored the
+// error matches fs.ErrNotExist
+func Load(name string) ([]byte, error) {
+	storage, err := localStorage()
+	if err != nil {
+		return nil, err
+	}
+	value := storage.Call("getItem", "ebiten-mines/"+name)
+	if value.IsNull() {
+		return nil, &fs.PathError{Op: "load", Path: name, Err: fs.ErrNotExist}
+	}
+	return []byte(value.String()), nil
+}
+
+// Save stores the data under a name in the browser's localStorage
+func Save(name string, data []byte) (err error) {
+	storage, err := localStorage()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("could not save '%s': %v", name, r)
+		}
+	}()
+	storage.Call("setItem", "ebiten-mines/"+name, string(data))
+	return nil
+}
+
+// Remove removes the data stored under a name
+func Remove(name string) error {
+	storage, err := localStorage()
+	if err != nil {
+		return err
+	}
+	storage.Call("removeItem", "ebiten-mines/"+name)
+	return nil
+}
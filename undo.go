@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/mevdschee/ebiten-mines/board"
+	"github.com/mevdschee/ebiten-mines/replay"
+)
+
+// undoMessage is shown on the board when a mine was hit in practice mode
+const undoMessage = "[Undo]"
+
+// undo takes back the last move in practice mode
+func (g *game) undo() {
+	if !g.board.CanUndo() {
+		return
+	}
+	g.record(replay.Undo, 0, 0)
+	g.board.Undo()
+	g.cancelPress(g.pressX, g.pressY)
+	g.hinting = false
+	g.message = ""
+	g.button = buttonPlaying
+	if g.board.State() == board.Lost {
+		g.button = buttonLost
+	}
+	g.updateHeatmap()
+}